@@ -2,26 +2,38 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
 )
 
 type Config struct {
-	DefaultFile string `json:"default_file"`
-	BaseDir     string `json:"base_dir"`
-	OutDir      string `json:"out_dir"`
-	DefaultExt  string `json:"default_ext"`
-	WikiLang    string `json:"wiki_lang"`
-	ProcessTopN int    `json:"process_top_n"`
+	DefaultFile     string         `json:"default_file"`
+	BaseDir         string         `json:"base_dir"`
+	OutDir          string         `json:"out_dir"`
+	DefaultExt      string         `json:"default_ext"`
+	WikiLang        string         `json:"wiki_lang"`
+	ProcessTopN     int            `json:"process_top_n"`
+	ProcessSort     string         `json:"process_sort"`
+	ProcessSampleMs int            `json:"process_sample_ms"`
+	KillTimeoutSec  int            `json:"kill_timeout_sec"`
+	KillDeny        KillPolicy     `json:"kill_deny"`
+	Watchdog        WatchdogConfig `json:"watchdog"`
 }
 
 func defaultConfig() Config {
 	return Config{
-		DefaultFile: "data/input.txt",
-		BaseDir:     "data",
-		OutDir:      "out",
-		DefaultExt:  ".txt",
-		WikiLang:    "fr",
-		ProcessTopN: 10,
+		DefaultFile:     "data/input.txt",
+		BaseDir:         "data",
+		OutDir:          "out",
+		DefaultExt:      ".txt",
+		WikiLang:        "fr",
+		ProcessTopN:     10,
+		ProcessSort:     "cpu",
+		ProcessSampleMs: 500,
+		KillTimeoutSec:  10,
+		KillDeny:        defaultKillPolicy(),
+		Watchdog:        defaultWatchdogConfig(),
 	}
 }
 
@@ -55,5 +67,33 @@ func loadConfig(path string) (Config, error) {
 	if raw.ProcessTopN > 0 {
 		cfg.ProcessTopN = raw.ProcessTopN
 	}
+	if raw.ProcessSort != "" {
+		if key := strings.ToLower(raw.ProcessSort); validSortKey(key) {
+			cfg.ProcessSort = key
+		} else {
+			fmt.Printf("process_sort inconnu (%s), tri par %s.\n", raw.ProcessSort, cfg.ProcessSort)
+		}
+	}
+	if raw.ProcessSampleMs > 0 {
+		cfg.ProcessSampleMs = raw.ProcessSampleMs
+	}
+	if raw.KillTimeoutSec > 0 {
+		cfg.KillTimeoutSec = raw.KillTimeoutSec
+	}
+	if len(raw.KillDeny.PIDs) > 0 {
+		cfg.KillDeny.PIDs = raw.KillDeny.PIDs
+	}
+	if len(raw.KillDeny.Names) > 0 {
+		cfg.KillDeny.Names = raw.KillDeny.Names
+	}
+	if len(raw.KillDeny.Users) > 0 {
+		cfg.KillDeny.Users = raw.KillDeny.Users
+	}
+	if raw.Watchdog.IntervalSec > 0 {
+		cfg.Watchdog.IntervalSec = raw.Watchdog.IntervalSec
+	}
+	if len(raw.Watchdog.Rules) > 0 {
+		cfg.Watchdog.Rules = raw.Watchdog.Rules
+	}
 	return cfg, nil
 }
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Le verrou est un verrou OS (flock / LockFileEx) pose sur le fichier cible
+// par un processus detenteur lance en mode --lock-daemon: il garde le fichier
+// ouvert et verrouille jusqu'a son arret. Le fichier .lock dans OutDir n'est
+// qu'une indication lisible (LockInfo: proprietaire, PID du detenteur, TTL).
+
+var errLockBusy = errors.New("fichier deja verrouille par un autre processus")
+
+// mode --lock-daemon: tourne au premier plan jusqu'a Ctrl-C, SIGTERM ou la
+// fin du TTL (0 = sans limite)
+func runLockDaemon(path string, ttl time.Duration) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := lockFile(file); err != nil {
+		return err
+	}
+	defer unlockFile(file)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+	// premiere ligne lue par startLockDaemon
+	fmt.Printf("LOCKED pid=%d file=%s\n", os.Getpid(), path)
+	var expired <-chan time.Time
+	if ttl > 0 {
+		expired = time.After(ttl)
+	}
+	select {
+	case <-stop:
+	case <-expired:
+	}
+	return nil
+}
+
+// mode --lock-status: un verrou pris puis relache aussitot veut dire libre
+func queryLock(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	err = lockFile(file)
+	if errors.Is(err, errLockBusy) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, unlockFile(file)
+}
+
+func startLockDaemon(path string, ttlMin int) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	// meme chemin que LockInfo.File: isLockDaemon le retrouve dans la ligne de commande
+	args := []string{"--lock-daemon", canonicalPath(path)}
+	if ttlMin > 0 {
+		args = append([]string{"--lock-ttl", strconv.Itoa(ttlMin)}, args...)
+	}
+	cmd := exec.Command(exe, args...)
+	// detache de la session: le verrou survit a la fermeture de l'outil
+	cmd.SysProcAttr = detachedProcAttr()
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	line, _ := bufio.NewReader(out).ReadString('\n')
+	out.Close()
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "LOCKED") {
+		cmd.Wait()
+		if line == "" {
+			line = "detenteur du verrou arrete sans verrouiller"
+		}
+		return 0, errors.New(strings.TrimPrefix(line, "ERREUR "))
+	}
+	// recolte le detenteur s'il est arrete pendant la session: un zombie
+	// serait vu comme vivant la ou l'OS ne donne pas l'etat (macOS)
+	go cmd.Wait()
+	return cmd.Process.Pid, nil
+}
+
+// signaux envoyes directement a l'OS: le detenteur est un vrai processus,
+// meme quand ProcessOps tourne sur un backend simule
+func stopLockDaemon(pid int, path string) error {
+	sig := osSignals{}
+	if !sig.Alive(pid) {
+		return nil
+	}
+	if !isLockDaemon(pid, path) {
+		// le PID du marqueur a ete recycle: rien a arreter
+		return nil
+	}
+	if err := sig.Signal(pid, "TERM"); err == nil && waitLockDaemonExit(pid, 2*time.Second) {
+		return nil
+	}
+	if err := sig.Signal(pid, "KILL"); err != nil {
+		return err
+	}
+	if !waitLockDaemonExit(pid, 2*time.Second) {
+		return fmt.Errorf("detenteur %d toujours actif", pid)
+	}
+	return nil
+}
+
+// le PID doit etre ce programme lance en --lock-daemon sur ce fichier; lu sur
+// le backend systeme, meme en simulation
+func isLockDaemon(pid int, path string) bool {
+	proc, err := newSystemBackend().Details(pid)
+	if err != nil {
+		return false
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	// Windows entoure de guillemets les arguments qui contiennent des espaces
+	cmdline := strings.ReplaceAll(proc.Command, `"`, "")
+	return strings.Contains(cmdline, filepath.Base(exe)) && strings.HasSuffix(cmdline, "--lock-daemon "+canonicalPath(path))
+}
+
+func waitLockDaemonExit(pid int, timeout time.Duration) bool {
+	sig := osSignals{}
+	deadline := time.Now().Add(timeout)
+	for sig.Alive(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
+
+func runLockStatus(cfg Config) {
+	path := askPath("Fichier", cfg.DefaultFile)
+	if !fileExists(path) {
+		fmt.Println("Fichier introuvable ou non valide.")
+		return
+	}
+	locked, err := queryLock(path)
+	if err != nil {
+		fmt.Printf("Erreur verrou: %v\n", err)
+		return
+	}
+	lockPath := lockPathForFile(path, cfg.OutDir)
+	if !fileExists(lockPath) {
+		if locked {
+			fmt.Println("Verrouille par un autre outil.")
+		} else {
+			fmt.Println("Libre.")
+		}
+		return
+	}
+	info, err := readLockInfo(lockPath)
+	if err != nil {
+		fmt.Printf("Erreur lecture lock: %v\n", err)
+		return
+	}
+	printLockInfo(info)
+	if stale := lockStaleReason(info, time.Now()); stale != "" {
+		fmt.Printf("Etat: perime (%s), OS: verrouille=%t\n", stale, locked)
+		return
+	}
+	fmt.Printf("Etat: actif, OS: verrouille=%t\n", locked)
+}
@@ -0,0 +1,77 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+	errorLockViolation      = syscall.Errno(33)
+	detachedProcess         = 0x8
+	// octet temoin bien au-dela de toute donnee reelle: son verrou exclusif
+	// marque le detenteur, sans gener la lecture du contenu
+	lockSentinelOffset = 1 << 62
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+// verrou partage sur le contenu: contrairement a flock il est obligatoire,
+// plus aucun processus ne peut y ecrire mais tous peuvent le lire. Le verrou
+// exclusif sur l'octet temoin empeche un second detenteur.
+func lockFile(file *os.File) error {
+	if err := lockRange(file, lockfileExclusiveLock|lockfileFailImmediately, lockSentinelOffset, 1); err != nil {
+		if err == errorLockViolation {
+			return errLockBusy
+		}
+		return err
+	}
+	if err := lockRange(file, lockfileFailImmediately, 0, lockSentinelOffset); err != nil {
+		unlockRange(file, lockSentinelOffset, 1)
+		if err == errorLockViolation {
+			return errLockBusy
+		}
+		return err
+	}
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	err := unlockRange(file, 0, lockSentinelOffset)
+	if err2 := unlockRange(file, lockSentinelOffset, 1); err == nil {
+		err = err2
+	}
+	return err
+}
+
+func lockRange(file *os.File, flags uint32, offset, length uint64) error {
+	ol := syscall.Overlapped{Offset: uint32(offset), OffsetHigh: uint32(offset >> 32)}
+	r, _, err := procLockFileEx.Call(file.Fd(), uintptr(flags), 0,
+		uintptr(uint32(length)), uintptr(uint32(length>>32)), uintptr(unsafe.Pointer(&ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func unlockRange(file *os.File, offset, length uint64) error {
+	ol := syscall.Overlapped{Offset: uint32(offset), OffsetHigh: uint32(offset >> 32)}
+	r, _, err := procUnlockFileEx.Call(file.Fd(), 0,
+		uintptr(uint32(length)), uintptr(uint32(length>>32)), uintptr(unsafe.Pointer(&ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP, HideWindow: true}
+}
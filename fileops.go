@@ -158,7 +158,7 @@ func writeLines(path string, lines []string) error {
 	if content != "" && !strings.HasSuffix(content, "\n") {
 		content += "\n"
 	}
-	return os.WriteFile(path, []byte(content), 0o644)
+	return writeFileChecked(path, []byte(content), "write")
 }
 
 func headLines(lines []string, n int) []string {
@@ -229,7 +229,7 @@ func writeReport(path string, summaries []FileSummary) error {
 		b.WriteString(fmt.Sprintf("Mots: %d | Moyenne: %.2f\n", s.WordCount, s.AvgWordLen))
 		b.WriteString("\n")
 	}
-	return os.WriteFile(path, []byte(b.String()), 0o644)
+	return writeFileChecked(path, []byte(b.String()), "report")
 }
 
 func writeIndex(path string, summaries []FileSummary) error {
@@ -239,7 +239,7 @@ func writeIndex(path string, summaries []FileSummary) error {
 	for _, s := range summaries {
 		b.WriteString(fmt.Sprintf("%s | %d | %s\n", s.Path, s.Size, formatTime(s.ModTime)))
 	}
-	return os.WriteFile(path, []byte(b.String()), 0o644)
+	return writeFileChecked(path, []byte(b.String()), "index")
 }
 
 func mergeFiles(dir, ext, outPath string) error {
@@ -262,5 +262,5 @@ func mergeFiles(dir, ext, outPath string) error {
 		b.WriteString(strings.Join(lines, "\n"))
 	}
 	b.WriteString("\n")
-	return os.WriteFile(outPath, []byte(b.String()), 0o644)
+	return writeFileChecked(outPath, []byte(b.String()), "merge")
 }
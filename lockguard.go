@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// dossier du registre des locks (OutDir), positionne par main
+var lockRegistryDir string
+
+type LockedFileError struct {
+	Path string
+	Info LockInfo
+}
+
+func (e *LockedFileError) Error() string {
+	msg := fmt.Sprintf("%s verrouille par %s le %s", e.Path, e.Info.owner(), formatTime(e.Info.Locked))
+	if e.Info.Reason != "" {
+		msg += " (" + e.Info.Reason + ")"
+	}
+	return msg
+}
+
+// verrou actif sur path d'apres le registre; un verrou perime ne bloque rien
+func activeLock(path string) (LockInfo, bool) {
+	if lockRegistryDir == "" {
+		return LockInfo{}, false
+	}
+	lockPath := lockPathForFile(path, lockRegistryDir)
+	if !fileExists(lockPath) {
+		return LockInfo{}, false
+	}
+	info, err := readLockInfo(lockPath)
+	if err != nil {
+		// marqueur illisible: on le considere comme actif
+		return LockInfo{File: canonicalPath(path)}, true
+	}
+	if lockStaleReason(info, time.Now()) != "" {
+		return LockInfo{}, false
+	}
+	return info, true
+}
+
+// a appeler avant d'ecrire dans un fichier: un fichier verrouille est refuse
+// sauf forcage explicite, trace dans l'audit comme le refus
+func checkWriteAllowed(path, op string) error {
+	info, locked := activeLock(path)
+	if !locked {
+		return nil
+	}
+	lockErr := &LockedFileError{Path: path, Info: info}
+	fmt.Printf("Attention: %v\n", lockErr)
+	if strings.ToUpper(readLine("Tapez FORCER pour ecrire malgre le verrou (vide = refuser): ")) != "FORCER" {
+		writeAuditLog(lockRegistryDir, fmt.Sprintf("LOCK REFUSE op=%s file=%s %s", op, path, info.auditFields()))
+		return lockErr
+	}
+	writeAuditLog(lockRegistryDir, fmt.Sprintf("LOCK OVERRIDE op=%s file=%s %s", op, path, info.auditFields()))
+	return nil
+}
+
+func writeFileChecked(path string, data []byte, op string) error {
+	if err := checkWriteAllowed(path, op); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func createFileChecked(path, op string) (*os.File, error) {
+	if err := checkWriteAllowed(path, op); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
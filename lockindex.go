@@ -0,0 +1,161 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// out/locks_index.json: nom du fichier .lock -> chemin canonique du fichier
+// verrouille, pour retrouver la cible sans ouvrir chaque marqueur
+func lockIndexPath(outDir string) string {
+	return filepath.Join(outDir, "locks_index.json")
+}
+
+// chemin absolu, liens symboliques resolus; insensible a la casse sous Windows
+func canonicalPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if runtime.GOOS == "windows" {
+		abs = strings.ToLower(abs)
+	}
+	return abs
+}
+
+// nom lisible + empreinte du chemin canonique: data/a/report.txt et
+// data/b/report.txt (ou report.md) n'ont plus le meme lock
+func lockNameForFile(filePath string) string {
+	canonical := canonicalPath(filePath)
+	sum := sha256.Sum256([]byte(canonical))
+	return sanitizeFileName(filepath.Base(canonical)) + "_" + hex.EncodeToString(sum[:])[:12] + ".lock"
+}
+
+func loadLockIndex(outDir string) (map[string]string, error) {
+	index := make(map[string]string)
+	data, err := os.ReadFile(lockIndexPath(outDir))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+func saveLockIndex(outDir string, index map[string]string) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockIndexPath(outDir), append(data, '\n'), 0o644)
+}
+
+func indexLock(lockPath, filePath string) error {
+	outDir := filepath.Dir(lockPath)
+	index, err := loadLockIndex(outDir)
+	if err != nil {
+		return err
+	}
+	index[filepath.Base(lockPath)] = canonicalPath(filePath)
+	return saveLockIndex(outDir, index)
+}
+
+func unindexLock(lockPath string) error {
+	outDir := filepath.Dir(lockPath)
+	index, err := loadLockIndex(outDir)
+	if err != nil {
+		return err
+	}
+	if _, ok := index[filepath.Base(lockPath)]; !ok {
+		return nil
+	}
+	delete(index, filepath.Base(lockPath))
+	return saveLockIndex(outDir, index)
+}
+
+// Renomme les locks de l'ancien schema (out/<stem>.lock) absents de l'index.
+// Le fichier cible vient des metadonnees du marqueur, sinon d'une recherche
+// par stem dans baseDir; sans cible unique, le lock est laisse en place.
+func migrateLegacyLocks(outDir, baseDir string) {
+	paths, err := filepath.Glob(filepath.Join(outDir, "*.lock"))
+	if err != nil || len(paths) == 0 {
+		return
+	}
+	index, err := loadLockIndex(outDir)
+	if err != nil {
+		fmt.Printf("Erreur lecture %s: %v\n", lockIndexPath(outDir), err)
+		return
+	}
+	changed := false
+	for _, oldPath := range paths {
+		name := filepath.Base(oldPath)
+		if _, ok := index[name]; ok {
+			continue
+		}
+		info, err := readLockInfo(oldPath)
+		if err != nil {
+			continue
+		}
+		if info.File == "" {
+			matches := findFilesByLockStem(baseDir, strings.TrimSuffix(name, ".lock"))
+			if len(matches) != 1 {
+				fmt.Printf("Lock %s non migre: %d fichier(s) candidat(s) dans %s\n", oldPath, len(matches), baseDir)
+				continue
+			}
+			info.File = canonicalPath(matches[0])
+		}
+		newPath := filepath.Join(outDir, lockNameForFile(info.File))
+		if newPath != oldPath {
+			if fileExists(newPath) {
+				fmt.Printf("Lock %s non migre: %s existe deja\n", oldPath, newPath)
+				continue
+			}
+			if err := writeLockInfo(newPath, info); err != nil {
+				fmt.Printf("Lock %s non migre: %v\n", oldPath, err)
+				continue
+			}
+			os.Remove(oldPath)
+			writeAuditLog(outDir, fmt.Sprintf("LOCK MIGRATE old=%s new=%s file=%s", oldPath, newPath, info.File))
+		}
+		index[filepath.Base(newPath)] = canonicalPath(info.File)
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	if err := saveLockIndex(outDir, index); err != nil {
+		fmt.Printf("Erreur ecriture %s: %v\n", lockIndexPath(outDir), err)
+	}
+}
+
+// ancien schema: stem du fichier (sans extension) passe par sanitizeFileName
+func findFilesByLockStem(baseDir, stem string) []string {
+	var matches []string
+	filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) == ".lock" {
+			return nil
+		}
+		base := d.Name()
+		if sanitizeFileName(strings.TrimSuffix(base, filepath.Ext(base))) == stem {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// contenu du marqueur out/<nom>.lock
+type LockInfo struct {
+	File   string    `json:"file"`
+	User   string    `json:"user"`
+	Host   string    `json:"host"`
+	PID    int       `json:"pid"`
+	Locked time.Time `json:"locked"`
+	Reason string    `json:"reason,omitempty"`
+	TTLMin int       `json:"ttl_min,omitempty"`
+}
+
+func (l LockInfo) expires() time.Time {
+	if l.TTLMin <= 0 {
+		return time.Time{}
+	}
+	return l.Locked.Add(time.Duration(l.TTLMin) * time.Minute)
+}
+
+func (l LockInfo) owner() string {
+	return fmt.Sprintf("%s@%s pid %d", valueOrNA(l.User), valueOrNA(l.Host), l.PID)
+}
+
+func (l LockInfo) auditFields() string {
+	return fmt.Sprintf("user=%s host=%s pid=%d", l.User, l.Host, l.PID)
+}
+
+func writeLockInfo(lockPath string, info LockInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockPath, append(data, '\n'), 0o644)
+}
+
+// les anciens marqueurs ("locked", puis holder_pid=/file=) sont relus sans
+// utilisateur ni hote, la date est celle du fichier
+func readLockInfo(lockPath string) (LockInfo, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return LockInfo{}, err
+	}
+	var info LockInfo
+	if json.Unmarshal(data, &info) == nil {
+		return info, nil
+	}
+	if st, err := os.Stat(lockPath); err == nil {
+		info.Locked = st.ModTime()
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "holder_pid":
+			info.PID, _ = strconv.Atoi(value)
+		case "file":
+			info.File = value
+		}
+	}
+	return info, nil
+}
+
+// vide si le verrou est actif, sinon la raison pour laquelle il est perime
+func lockStaleReason(info LockInfo, now time.Time) string {
+	if exp := info.expires(); !exp.IsZero() && now.After(exp) {
+		return "expire"
+	}
+	if info.PID <= 0 {
+		return "aucun detenteur"
+	}
+	// le PID d'une autre machine ne peut pas etre verifie ici
+	if host, _ := os.Hostname(); info.Host != "" && info.Host != host {
+		return ""
+	}
+	if !(osSignals{}).Alive(info.PID) {
+		return "detenteur absent"
+	}
+	if info.File != "" {
+		if locked, err := queryLock(info.File); err == nil && !locked {
+			return "verrou OS absent"
+		}
+	}
+	return ""
+}
+
+// arrete le detenteur s'il tourne sur cette machine, puis retire le marqueur
+func releaseLock(lockPath string, info LockInfo) error {
+	host, _ := os.Hostname()
+	if info.PID > 0 && (info.Host == "" || info.Host == host) {
+		if err := stopLockDaemon(info.PID, info.File); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(lockPath); err != nil {
+		return err
+	}
+	return unindexLock(lockPath)
+}
+
+type lockEntry struct {
+	Path  string
+	Info  LockInfo
+	Stale string
+	Err   error
+}
+
+func listLocks(outDir string) ([]lockEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(outDir, "*.lock"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	now := time.Now()
+	var entries []lockEntry
+	for _, p := range paths {
+		info, err := readLockInfo(p)
+		e := lockEntry{Path: p, Info: info, Err: err}
+		if err == nil {
+			e.Stale = lockStaleReason(info, now)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func printLockInfo(info LockInfo) {
+	fmt.Printf("Fichier: %s\n", valueOrNA(info.File))
+	fmt.Printf("Pose par: %s le %s\n", info.owner(), formatTime(info.Locked))
+	if exp := info.expires(); !exp.IsZero() {
+		fmt.Printf("Expire: %s\n", formatTime(exp))
+	}
+	if info.Reason != "" {
+		fmt.Printf("Raison: %s\n", info.Reason)
+	}
+}
+
+func runListLocks(cfg Config) {
+	entries, err := listLocks(cfg.OutDir)
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Println("Aucun verrou.")
+		return
+	}
+	var stale []int
+	for i, e := range entries {
+		if e.Err != nil {
+			fmt.Printf("%3d) %s | erreur lecture: %v\n", i+1, e.Path, e.Err)
+			continue
+		}
+		ttl := "sans TTL"
+		if exp := e.Info.expires(); !exp.IsZero() {
+			ttl = "expire " + formatTime(exp)
+		}
+		state := "actif"
+		if e.Stale != "" {
+			state = "PERIME (" + e.Stale + ")"
+			stale = append(stale, i)
+		}
+		fmt.Printf("%3d) %s | %s | %s | %s | %s | %s\n", i+1, valueOrNA(e.Info.File), e.Info.owner(),
+			formatTime(e.Info.Locked), ttl, valueOrNA(e.Info.Reason), state)
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	line := strings.ToLower(readLine(fmt.Sprintf("%d verrou(s) perime(s). Numero a casser (all = tous, vide = retour): ", len(stale))))
+	if line == "" {
+		return
+	}
+	var selected []int
+	if line == "all" {
+		selected = stale
+	} else {
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 1 || n > len(entries) || entries[n-1].Stale == "" {
+			fmt.Println("Numero invalide (seuls les verrous perimes peuvent etre casses ici).")
+			return
+		}
+		selected = []int{n - 1}
+	}
+	if !confirmAction(fmt.Sprintf("Casser %d verrou(s)", len(selected))) {
+		fmt.Println("Annule.")
+		return
+	}
+	for _, i := range selected {
+		e := entries[i]
+		if err := releaseLock(e.Path, e.Info); err != nil {
+			fmt.Printf("%s: erreur: %v\n", e.Path, err)
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("LOCK BREAK FAIL lock=%s file=%s err=%v", e.Path, e.Info.File, err))
+			continue
+		}
+		fmt.Printf("%s: casse\n", e.Path)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("LOCK BREAK OK lock=%s file=%s %s stale=%q",
+			e.Path, e.Info.File, e.Info.auditFields(), e.Stale))
+	}
+}
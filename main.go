@@ -3,14 +3,51 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 func main() {
 	configPath := flag.String("config", "config.json", "chemin vers le fichier de config")
+	rlimits := flag.String("rlimits", "", "usage interne: applique des limites puis execute la commande apres --")
+	lockDaemon := flag.String("lock-daemon", "", "garde un verrou OS sur ce fichier jusqu'a Ctrl-C/SIGTERM")
+	lockTTL := flag.Int("lock-ttl", 0, "avec --lock-daemon: relache le verrou apres N minutes (0 = jamais)")
+	lockStatus := flag.String("lock-status", "", "indique si ce fichier est verrouille (code 1) ou libre (code 0)")
+	fakeProcs := flag.String("fake-procs", "", "simulation: ProcessOps travaille sur un snapshot (json/csv) au lieu des vrais processus")
 	flag.Parse()
 
+	if *rlimits != "" {
+		if err := execWithLimits(*rlimits, flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur limites: %v\n", err)
+			os.Exit(126)
+		}
+		return
+	}
+
+	if *lockDaemon != "" {
+		if err := runLockDaemon(*lockDaemon, time.Duration(*lockTTL)*time.Minute); err != nil {
+			// sur stdout: c'est le canal lu par le lanceur (startLockDaemon)
+			fmt.Printf("ERREUR %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *lockStatus != "" {
+		locked, err := queryLock(*lockStatus)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur verrou: %v\n", err)
+			os.Exit(2)
+		}
+		if locked {
+			fmt.Println("verrouille")
+			os.Exit(1)
+		}
+		fmt.Println("libre")
+		return
+	}
+
 	cfg, err := loadConfig(*configPath)
 	if err != nil {
 		fmt.Printf("Config introuvable (%s), utilisation des valeurs par defaut.\n", *configPath)
@@ -20,6 +57,17 @@ func main() {
 		fmt.Printf("Erreur creation dossier out: %v\n", err)
 		return
 	}
+	lockRegistryDir = cfg.OutDir
+
+	if *fakeProcs != "" {
+		snap, err := loadSnapshot(*fakeProcs)
+		if err != nil {
+			fmt.Printf("Erreur lecture snapshot %s: %v\n", *fakeProcs, err)
+			return
+		}
+		processBackend = newFakeBackend(snap.Processes)
+		fmt.Printf("Mode simulation: %d processus charges depuis %s, aucun signal reel.\n", len(snap.Processes), *fakeProcs)
+	}
 
 	currentFile := cfg.DefaultFile
 	if !fileExists(currentFile) {
@@ -77,6 +125,10 @@ func runFileAnalysis(cfg Config, currentFile *string) {
 		return
 	}
 	*currentFile = path
+	if info, locked := activeLock(path); locked {
+		// l'analyse ne fait que lire le fichier: elle reste permise
+		fmt.Printf("Fichier verrouille par %s: ouvert en lecture seule.\n", info.owner())
+	}
 
 	summary, lines, err := getFileSummary(path)
 	if err != nil {
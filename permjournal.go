@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// etat d'un fichier avant son passage en read-only, pour pouvoir le restaurer
+type PermissionChange struct {
+	File     string      `json:"file"`
+	OldMode  os.FileMode `json:"old_mode"`
+	NewMode  os.FileMode `json:"new_mode"`
+	OldAttrs string      `json:"old_attrs,omitempty"`
+	Changed  time.Time   `json:"changed"`
+	Reverted bool        `json:"reverted"`
+}
+
+func permissionJournalPath(outDir string) string {
+	return filepath.Join(outDir, "permissions_journal.json")
+}
+
+func loadPermissionJournal(outDir string) ([]PermissionChange, error) {
+	data, err := os.ReadFile(permissionJournalPath(outDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []PermissionChange
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func savePermissionJournal(outDir string, entries []PermissionChange) error {
+	if err := ensureDir(outDir); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(permissionJournalPath(outDir), append(data, '\n'), 0o644)
+}
+
+// un fichier deja passe en read-only garde sa premiere entree: c'est elle
+// qui contient l'etat d'origine
+func pendingPermissionChange(entries []PermissionChange, file string) int {
+	for i, e := range entries {
+		if !e.Reverted && e.File == file {
+			return i
+		}
+	}
+	return -1
+}
+
+func readPermissions(path string) (os.FileMode, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, "", err
+	}
+	if runtime.GOOS != "windows" {
+		return info.Mode().Perm(), "", nil
+	}
+	attrs, err := windowsAttributes(path)
+	return info.Mode().Perm(), attrs, err
+}
+
+// sortie de attrib: lettres des attributs puis chemin complet
+func windowsAttributes(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command("attrib", abs).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	line := strings.TrimSpace(string(out))
+	idx := strings.Index(strings.ToLower(line), strings.ToLower(abs))
+	if idx < 0 {
+		return "", fmt.Errorf("sortie attrib inattendue: %s", line)
+	}
+	return strings.Join(strings.Fields(line[:idx]), ""), nil
+}
+
+func restorePermissions(e PermissionChange) error {
+	if runtime.GOOS == "windows" {
+		flag := "-R"
+		if strings.Contains(e.OldAttrs, "R") {
+			flag = "+R"
+		}
+		cmd := exec.Command("attrib", flag, e.File)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return os.Chmod(e.File, e.OldMode)
+}
+
+func formatPermissions(mode os.FileMode, attrs string) string {
+	if attrs != "" {
+		return fmt.Sprintf("%04o [%s]", uint32(mode), attrs)
+	}
+	return fmt.Sprintf("%04o", uint32(mode))
+}
+
+func runRestorePermissions(cfg Config) {
+	entries, err := loadPermissionJournal(cfg.OutDir)
+	if err != nil {
+		fmt.Printf("Erreur lecture journal: %v\n", err)
+		return
+	}
+	var pending []int
+	for i, e := range entries {
+		if e.Reverted {
+			continue
+		}
+		pending = append(pending, i)
+		fmt.Printf("%3d) %s | %s -> %s | %s\n", len(pending), e.File,
+			formatPermissions(e.OldMode, e.OldAttrs), formatPermissions(e.NewMode, ""), formatTime(e.Changed))
+	}
+	if len(pending) == 0 {
+		fmt.Println("Aucune permission a restaurer.")
+		return
+	}
+	line := strings.ToLower(readLine("Numeros a restaurer (ex: 1,3; all = tous, vide = retour): "))
+	if line == "" {
+		return
+	}
+	var selected []int
+	if line == "all" {
+		selected = pending
+	} else {
+		for _, part := range strings.Split(line, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 1 || n > len(pending) {
+				fmt.Printf("Numero invalide: %s\n", part)
+				return
+			}
+			selected = append(selected, pending[n-1])
+		}
+	}
+	if !confirmAction(fmt.Sprintf("Restaurer %d fichier(s)", len(selected))) {
+		fmt.Println("Annule.")
+		return
+	}
+
+	for _, i := range selected {
+		e := &entries[i]
+		if e.Reverted {
+			continue
+		}
+		if mode, _, err := readPermissions(e.File); err != nil {
+			fmt.Printf("%s: %v\n", e.File, err)
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("PERM RESTORE FAIL file=%s err=%v", e.File, err))
+			continue
+		} else if mode != e.NewMode {
+			fmt.Printf("%s: permissions modifiees depuis (%04o), restauration quand meme\n", e.File, uint32(mode))
+		}
+		if err := restorePermissions(*e); err != nil {
+			fmt.Printf("%s: erreur: %v\n", e.File, err)
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("PERM RESTORE FAIL file=%s mode=%s err=%v", e.File, formatPermissions(e.OldMode, e.OldAttrs), err))
+			continue
+		}
+		e.Reverted = true
+		fmt.Printf("%s: restaure (%s)\n", e.File, formatPermissions(e.OldMode, e.OldAttrs))
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("PERM RESTORE OK file=%s mode=%s", e.File, formatPermissions(e.OldMode, e.OldAttrs)))
+	}
+	if err := savePermissionJournal(cfg.OutDir, entries); err != nil {
+		fmt.Printf("Erreur ecriture journal: %v\n", err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// acces aux processus: liste, details d'un PID et envoi de signaux. Le reste
+// de ProcessOps (filtres, confirmation, politique, audit) passe par cette
+// interface, ce qui permet de le faire tourner sur fakeBackend.
+type ProcessBackend interface {
+	List() ([]ProcessInfo, error)
+	Details(pid int) (ProcessInfo, error)
+	Signal(pid int, name string) error
+	Alive(pid int) bool
+}
+
+var processBackend = newSystemBackend()
+
+func newSystemBackend() ProcessBackend {
+	switch runtime.GOOS {
+	case "windows":
+		return windowsBackend{}
+	case "darwin":
+		return darwinBackend{}
+	case "linux":
+		return linuxBackend{}
+	default:
+		return unsupportedBackend{}
+	}
+}
+
+type linuxBackend struct{ osSignals }
+
+func (linuxBackend) List() ([]ProcessInfo, error) {
+	return listProcessesLinux()
+}
+
+func (linuxBackend) Details(pid int) (ProcessInfo, error) {
+	return getProcessLinux(pid)
+}
+
+type darwinBackend struct{ osSignals }
+
+func (darwinBackend) List() ([]ProcessInfo, error) {
+	return listProcessesDarwin()
+}
+
+func (b darwinBackend) Details(pid int) (ProcessInfo, error) {
+	return detailsFromList(b, pid)
+}
+
+type windowsBackend struct{ osSignals }
+
+func (windowsBackend) List() ([]ProcessInfo, error) {
+	return listProcessesWindows()
+}
+
+func (b windowsBackend) Details(pid int) (ProcessInfo, error) {
+	return detailsFromList(b, pid)
+}
+
+type unsupportedBackend struct{}
+
+func (unsupportedBackend) List() ([]ProcessInfo, error) {
+	return nil, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func (unsupportedBackend) Details(pid int) (ProcessInfo, error) {
+	return ProcessInfo{}, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func (unsupportedBackend) Signal(pid int, name string) error {
+	return fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func (unsupportedBackend) Alive(pid int) bool {
+	return false
+}
+
+// sans acces direct a un PID (ps, CIM), on relit la liste complete
+func detailsFromList(b ProcessBackend, pid int) (ProcessInfo, error) {
+	procs, err := b.List()
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	proc, found := findProcessByPID(procs, pid)
+	if !found {
+		return ProcessInfo{}, fmt.Errorf("PID %d absent", pid)
+	}
+	return proc, nil
+}
+
+func simulationMode() bool {
+	_, fake := processBackend.(*fakeBackend)
+	return fake
+}
+
+func listProcesses() ([]ProcessInfo, error) {
+	return processBackend.List()
+}
+
+func getProcess(pid int) (ProcessInfo, error) {
+	return processBackend.Details(pid)
+}
+
+func sendSignal(pid int, name string) error {
+	return processBackend.Signal(pid, name)
+}
+
+func processAlive(pid int) bool {
+	return processBackend.Alive(pid)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// backend en memoire: aucun signal n'atteint un vrai processus. Les signaux
+// d'arret retirent le processus, STOP/CONT changent son etat.
+type fakeBackend struct {
+	procs map[int]ProcessInfo
+	// Stubborn: PID qui ignorent tout sauf KILL (teste l'escalade)
+	Stubborn map[int]bool
+	// Sent: signaux recus, dans l'ordre
+	Sent []fakeSignal
+}
+
+type fakeSignal struct {
+	PID    int
+	Signal string
+}
+
+func newFakeBackend(procs []ProcessInfo) *fakeBackend {
+	b := &fakeBackend{procs: make(map[int]ProcessInfo), Stubborn: make(map[int]bool)}
+	for _, p := range procs {
+		b.procs[p.PID] = p
+	}
+	return b
+}
+
+func (b *fakeBackend) List() ([]ProcessInfo, error) {
+	out := make([]ProcessInfo, 0, len(b.procs))
+	for _, p := range b.procs {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PID < out[j].PID })
+	return out, nil
+}
+
+func (b *fakeBackend) Details(pid int) (ProcessInfo, error) {
+	p, ok := b.procs[pid]
+	if !ok {
+		return ProcessInfo{}, fmt.Errorf("PID %d absent", pid)
+	}
+	return p, nil
+}
+
+func (b *fakeBackend) Signal(pid int, name string) error {
+	if !signalSupported(name) {
+		return fmt.Errorf("signal inconnu: %s", name)
+	}
+	p, ok := b.procs[pid]
+	if !ok {
+		return fmt.Errorf("PID %d absent", pid)
+	}
+	b.Sent = append(b.Sent, fakeSignal{PID: pid, Signal: name})
+	switch name {
+	case "STOP":
+		p.State = "T"
+		b.procs[pid] = p
+	case "CONT":
+		p.State = "S"
+		b.procs[pid] = p
+	case "USR1", "USR2":
+	case "KILL":
+		delete(b.procs, pid)
+	default:
+		if !b.Stubborn[pid] {
+			delete(b.procs, pid)
+		}
+	}
+	return nil
+}
+
+func (b *fakeBackend) Alive(pid int) bool {
+	p, ok := b.procs[pid]
+	return ok && p.State != "Z"
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type ContainerInfo struct {
+	PID         int
+	Cgroup      string
+	Runtime     string
+	ContainerID string
+	Namespaces  map[string]uint64
+}
+
+// ordre d'affichage des namespaces lus dans /proc/<pid>/ns
+var namespaceKinds = []string{"pid", "net", "mnt", "uts", "ipc", "user", "cgroup"}
+
+func (c ContainerInfo) shortID() string {
+	if c.ContainerID == "" {
+		return "-"
+	}
+	return truncateID(c.ContainerID)
+}
+
+func truncateID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+type containerRow struct {
+	Proc ProcessInfo
+	Info ContainerInfo
+}
+
+func warnContainerScope() {
+	reason, inside := selfInContainer()
+	if !inside {
+		return
+	}
+	fmt.Printf("Attention: l'outil tourne dans un conteneur (%s), seuls les processus du conteneur sont visibles.\n", reason)
+}
+
+func runContainerView() {
+	procs, err := listProcesses()
+	if err != nil {
+		fmt.Printf("Erreur liste: %v\n", err)
+		return
+	}
+	var rows []containerRow
+	var lastErr error
+	counts := make(map[string]int)
+	runtimes := make(map[string]string)
+	for _, p := range procs {
+		info, err := processContainer(p.PID)
+		if err != nil {
+			// processus termine entre la liste et la lecture
+			lastErr = err
+			continue
+		}
+		rows = append(rows, containerRow{Proc: p, Info: info})
+		counts[info.ContainerID]++
+		if info.ContainerID != "" {
+			runtimes[info.ContainerID] = info.Runtime
+		}
+	}
+	if len(rows) == 0 {
+		if lastErr != nil {
+			fmt.Printf("Erreur: %v\n", lastErr)
+		} else {
+			fmt.Println("Aucun processus.")
+		}
+		return
+	}
+
+	fmt.Printf("Hote: %d processus\n", counts[""])
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Printf("Conteneur %s (%s): %d processus\n", truncateID(id), runtimes[id], counts[id])
+	}
+
+	line := strings.ToLower(readLine("Filtre (vide = tous, h = hote seulement, ID ou prefixe = ce conteneur): "))
+	rows, err = filterContainerRows(rows, line)
+	if err != nil {
+		fmt.Printf("Erreur filtre: %v\n", err)
+		return
+	}
+	if len(rows) == 0 {
+		fmt.Println("Aucun processus.")
+		return
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Proc.PID < rows[j].Proc.PID })
+	printContainerRows(rows)
+}
+
+func filterContainerRows(rows []containerRow, filter string) ([]containerRow, error) {
+	switch filter {
+	case "":
+		return rows, nil
+	case "h":
+		var out []containerRow
+		for _, r := range rows {
+			if r.Info.ContainerID == "" {
+				out = append(out, r)
+			}
+		}
+		return out, nil
+	}
+	// un prefixe doit designer un seul conteneur
+	var id string
+	for _, r := range rows {
+		cid := r.Info.ContainerID
+		if cid == "" || !strings.HasPrefix(cid, filter) || cid == id {
+			continue
+		}
+		if id != "" {
+			return nil, fmt.Errorf("prefixe ambigu: %s", filter)
+		}
+		id = cid
+	}
+	if id == "" {
+		return nil, fmt.Errorf("conteneur introuvable: %s", filter)
+	}
+	var out []containerRow
+	for _, r := range rows {
+		if r.Info.ContainerID == id {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func printContainerRows(rows []containerRow) {
+	var header strings.Builder
+	fmt.Fprintf(&header, "%7s %-16s %-12s", "PID", "NOM", "CONTENEUR")
+	for _, kind := range namespaceKinds {
+		fmt.Fprintf(&header, " %-10s", strings.ToUpper(kind)+"NS")
+	}
+	fmt.Println(header.String() + " CGROUP")
+	for _, r := range rows {
+		var line strings.Builder
+		fmt.Fprintf(&line, "%7d %-16s %-12s", r.Proc.PID, truncate(r.Proc.Name, 16), r.Info.shortID())
+		for _, kind := range namespaceKinds {
+			fmt.Fprintf(&line, " %-10s", namespaceLabel(r.Info.Namespaces, kind))
+		}
+		fmt.Println(line.String() + " " + valueOrNA(r.Info.Cgroup))
+	}
+}
+
+func namespaceLabel(ns map[string]uint64, kind string) string {
+	if inode, ok := ns[kind]; ok {
+		return fmt.Sprintf("%d", inode)
+	}
+	return "n/a"
+}
@@ -0,0 +1,103 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// docker: /docker/<id> ou docker-<id>.scope, containerd/CRI: cri-containerd-<id>.scope
+// ou /kubepods/.../<id>, podman: libpod-<id>.scope
+var containerIDPattern = regexp.MustCompile(`(?:^|[/-])([0-9a-f]{64})(?:\.scope)?(?:/|$)`)
+
+func processContainer(pid int) (ContainerInfo, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup"))
+	if err != nil {
+		return ContainerInfo{}, err
+	}
+	info := parseProcCgroup(string(data))
+	info.PID = pid
+	info.Namespaces = make(map[string]uint64)
+	for _, kind := range namespaceKinds {
+		link, err := os.Readlink(filepath.Join(dir, "ns", kind))
+		if err != nil {
+			continue
+		}
+		if inode, ok := namespaceInode(link); ok {
+			info.Namespaces[kind] = inode
+		}
+	}
+	return info, nil
+}
+
+// lignes "hierarchie:controleurs:chemin"; en cgroup v1 le chemin le plus
+// parlant n'est pas toujours sur la meme ligne: on garde le premier non racine
+func parseProcCgroup(data string) ContainerInfo {
+	var info ContainerInfo
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+		if info.ContainerID == "" {
+			if m := containerIDPattern.FindStringSubmatch(path); m != nil {
+				info.ContainerID = m[1]
+				info.Runtime = containerRuntime(path)
+				info.Cgroup = path
+			}
+		}
+		if info.Cgroup == "" && path != "/" {
+			info.Cgroup = path
+		}
+	}
+	if info.Cgroup == "" {
+		info.Cgroup = "/"
+	}
+	return info
+}
+
+func containerRuntime(path string) string {
+	switch {
+	case strings.Contains(path, "containerd"), strings.Contains(path, "kubepods"):
+		return "containerd"
+	case strings.Contains(path, "docker"):
+		return "docker"
+	case strings.Contains(path, "libpod"):
+		return "podman"
+	}
+	return "?"
+}
+
+// format du lien: "pid:[4026531836]"
+func namespaceInode(link string) (uint64, bool) {
+	start := strings.IndexByte(link, '[')
+	end := strings.LastIndexByte(link, ']')
+	if start < 0 || end <= start {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(link[start+1:end], 10, 64)
+	return inode, err == nil
+}
+
+func selfInContainer() (string, bool) {
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return "/.dockerenv", true
+	}
+	if _, err := os.Stat("/run/.containerenv"); err == nil {
+		return "/run/.containerenv", true
+	}
+	// positionnee par podman, systemd-nspawn, lxc
+	if name := os.Getenv("container"); name != "" {
+		return "container=" + name, true
+	}
+	if info, err := processContainer(os.Getpid()); err == nil && info.ContainerID != "" {
+		return info.Runtime + " " + truncateID(info.ContainerID), true
+	}
+	return "", false
+}
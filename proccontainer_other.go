@@ -0,0 +1,16 @@
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func processContainer(pid int) (ContainerInfo, error) {
+	return ContainerInfo{}, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func selfInContainer() (string, bool) {
+	return "", false
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type OpenFile struct {
+	FD     int
+	Target string
+}
+
+type SocketInfo struct {
+	Proto  string
+	Local  string
+	Remote string
+	State  string
+	Inode  uint64
+}
+
+type ProcessFiles struct {
+	PID     int
+	Cwd     string
+	Exe     string
+	Files   []OpenFile
+	Sockets []SocketInfo
+}
+
+type FileUser struct {
+	Proc ProcessInfo
+	FDs  []int
+}
+
+func runProcessFiles() {
+	pid, ok := readPID("PID a inspecter: ")
+	if !ok {
+		return
+	}
+	pf, err := processFiles(pid)
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		return
+	}
+	printProcessFiles(pf)
+}
+
+func printProcessFiles(pf ProcessFiles) {
+	fmt.Printf("PID: %d\n", pf.PID)
+	fmt.Printf("Executable: %s\n", valueOrNA(pf.Exe))
+	fmt.Printf("Repertoire courant: %s\n", valueOrNA(pf.Cwd))
+	fmt.Printf("Descripteurs ouverts: %d\n", len(pf.Files))
+	for _, f := range pf.Files {
+		fmt.Printf("%5d -> %s\n", f.FD, f.Target)
+	}
+	if len(pf.Sockets) == 0 {
+		fmt.Println("Sockets: aucun")
+		return
+	}
+	fmt.Printf("Sockets: %d\n", len(pf.Sockets))
+	fmt.Printf("%-6s %-40s %-40s %-12s %s\n", "PROTO", "LOCAL", "DISTANT", "ETAT", "INODE")
+	for _, s := range pf.Sockets {
+		fmt.Printf("%-6s %-40s %-40s %-12s %d\n", s.Proto, s.Local, s.Remote, s.State, s.Inode)
+	}
+}
+
+func runFileUsers(def string) {
+	path := askPath("Fichier", def)
+	users, err := processesUsingFile(path)
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		return
+	}
+	if len(users) == 0 {
+		fmt.Println("Aucun processus n'a ce fichier ouvert.")
+		return
+	}
+	printFileUsers(users)
+}
+
+func printFileUsers(users []FileUser) {
+	for _, u := range users {
+		fds := make([]string, len(u.FDs))
+		for i, fd := range u.FDs {
+			fds[i] = fmt.Sprint(fd)
+		}
+		fmt.Printf("%7d %-12s %-20s fd=%s\n", u.Proc.PID, truncate(u.Proc.User, 12), truncate(u.Proc.Name, 20), strings.Join(fds, ","))
+	}
+}
+
+// avertit avant un lock / read-only si des processus utilisent encore le fichier
+func warnFileUsers(path string) {
+	users, err := processesUsingFile(path)
+	if err != nil || len(users) == 0 {
+		return
+	}
+	fmt.Printf("Attention: %d processus ont ce fichier ouvert:\n", len(users))
+	printFileUsers(users)
+}
+
+func valueOrNA(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+func processFiles(pid int) (ProcessFiles, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	entries, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return ProcessFiles{}, err
+	}
+	pf := ProcessFiles{PID: pid}
+	pf.Cwd, _ = os.Readlink(filepath.Join(dir, "cwd"))
+	pf.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+
+	var sockets map[uint64]SocketInfo
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(dir, "fd", entry.Name()))
+		if err != nil {
+			continue
+		}
+		pf.Files = append(pf.Files, OpenFile{FD: fd, Target: target})
+		inode, ok := socketInode(target)
+		if !ok {
+			continue
+		}
+		if sockets == nil {
+			// tables du namespace reseau du processus, pas de l'outil
+			sockets = readSocketTables(filepath.Join(dir, "net"))
+		}
+		if s, found := sockets[inode]; found {
+			pf.Sockets = append(pf.Sockets, s)
+		} else {
+			pf.Sockets = append(pf.Sockets, SocketInfo{Proto: "?", Inode: inode})
+		}
+	}
+	sort.Slice(pf.Files, func(i, j int) bool { return pf.Files[i].FD < pf.Files[j].FD })
+	return pf, nil
+}
+
+func processesUsingFile(path string) ([]FileUser, error) {
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+	if _, err := os.Stat(target); err != nil {
+		return nil, err
+	}
+
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	var users []FileUser
+	for _, p := range procs {
+		fdDir := filepath.Join("/proc", strconv.Itoa(p.PID), "fd")
+		entries, err := os.ReadDir(fdDir)
+		if err != nil {
+			// pas les droits sur ce processus
+			continue
+		}
+		var fds []int
+		for _, entry := range entries {
+			link, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+			if err != nil || link != target {
+				continue
+			}
+			if fd, err := strconv.Atoi(entry.Name()); err == nil {
+				fds = append(fds, fd)
+			}
+		}
+		if len(fds) > 0 {
+			sort.Ints(fds)
+			users = append(users, FileUser{Proc: p, FDs: fds})
+		}
+	}
+	return users, nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func processFiles(pid int) (ProcessFiles, error) {
+	return ProcessFiles{}, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func processesUsingFile(path string) ([]FileUser, error) {
+	return nil, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func listeningSockets() ([]PortOwner, error) {
+	return nil, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type processGroup struct {
+	Key   string
+	Count int
+	RSS   uint64
+	CPU   float64
+}
+
+var groupSortKeys = []string{"cpu", "mem", "count"}
+
+func runProcessGroups(cfg Config) {
+	topN := readIntWithDefault("Top N", cfg.ProcessTopN)
+	key := strings.ToLower(readLine(fmt.Sprintf("Tri (%s) [cpu]: ", strings.Join(groupSortKeys, "/"))))
+	switch key {
+	case "":
+		key = "cpu"
+	case "cpu", "mem", "count":
+	default:
+		fmt.Println("Cle de tri inconnue, cpu utilise.")
+		key = "cpu"
+	}
+	procs, err := sampleProcesses(time.Duration(cfg.ProcessSampleMs) * time.Millisecond)
+	if err != nil {
+		fmt.Printf("Erreur liste: %v\n", err)
+		return
+	}
+
+	byUser := groupProcesses(procs, func(p ProcessInfo) string { return p.User }, key)
+	byName := groupProcesses(procs, func(p ProcessInfo) string { return p.Name }, key)
+	fmt.Printf("Par utilisateur (%d groupes):\n", len(byUser))
+	printProcessGroups("USER", byUser, topN)
+	fmt.Println()
+	fmt.Printf("Par application (%d groupes):\n", len(byName))
+	printProcessGroups("NOM", byName, topN)
+}
+
+func groupProcesses(procs []ProcessInfo, keyOf func(ProcessInfo) string, sortKey string) []processGroup {
+	index := make(map[string]int)
+	var groups []processGroup
+	for _, p := range procs {
+		k := keyOf(p)
+		if k == "" {
+			k = "?"
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, processGroup{Key: k})
+		}
+		groups[i].Count++
+		groups[i].RSS += p.RSS
+		groups[i].CPU += p.CPU
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		switch sortKey {
+		case "mem":
+			if a.RSS != b.RSS {
+				return a.RSS > b.RSS
+			}
+		case "count":
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+		default:
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU
+			}
+		}
+		return a.Key < b.Key
+	})
+	return groups
+}
+
+func printProcessGroups(label string, groups []processGroup, topN int) {
+	if topN > 0 && topN < len(groups) {
+		groups = groups[:topN]
+	}
+	fmt.Printf("%-24s %7s %10s %8s\n", label, "PROCS", "RSS", "CPU%")
+	for _, g := range groups {
+		fmt.Printf("%-24s %7d %10s %8.1f\n", truncate(g.Key, 24), g.Count, formatBytes(g.RSS), g.CPU)
+	}
+}
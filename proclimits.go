@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RunLimits struct {
+	CPUSeconds     uint64
+	AddressSpaceMB uint64
+	OpenFiles      uint64
+	MaxProcs       uint64
+	Nice           int
+	SetNice        bool
+}
+
+func (l RunLimits) active() bool {
+	return l.CPUSeconds > 0 || l.AddressSpaceMB > 0 || l.OpenFiles > 0 || l.MaxProcs > 0 || l.SetNice
+}
+
+func (l RunLimits) String() string {
+	var parts []string
+	if l.CPUSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("cpu=%d", l.CPUSeconds))
+	}
+	if l.AddressSpaceMB > 0 {
+		parts = append(parts, fmt.Sprintf("as=%d", l.AddressSpaceMB))
+	}
+	if l.OpenFiles > 0 {
+		parts = append(parts, fmt.Sprintf("nofile=%d", l.OpenFiles))
+	}
+	if l.MaxProcs > 0 {
+		parts = append(parts, fmt.Sprintf("nproc=%d", l.MaxProcs))
+	}
+	if l.SetNice {
+		parts = append(parts, fmt.Sprintf("nice=%d", l.Nice))
+	}
+	return strings.Join(parts, ",")
+}
+
+// format: cpu=secondes,as=Mo,nofile=N,nproc=N,nice=N (chaque cle est optionnelle)
+func parseRunLimits(value string) (RunLimits, error) {
+	var l RunLimits
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return l, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return l, fmt.Errorf("limite invalide: %s", part)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		raw = strings.TrimSpace(raw)
+		if key == "nice" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < -20 || n > 19 {
+				return l, fmt.Errorf("nice invalide (-20..19): %s", raw)
+			}
+			l.Nice, l.SetNice = n, true
+			continue
+		}
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || n == 0 {
+			return l, fmt.Errorf("valeur invalide pour %s: %s", key, raw)
+		}
+		switch key {
+		case "cpu":
+			l.CPUSeconds = n
+		case "as":
+			l.AddressSpaceMB = n
+		case "nofile":
+			l.OpenFiles = n
+		case "nproc":
+			l.MaxProcs = n
+		default:
+			return l, fmt.Errorf("limite inconnue: %s", key)
+		}
+	}
+	return l, nil
+}
+
+type PriorityChange struct {
+	PID       int       `json:"pid"`
+	Name      string    `json:"name"`
+	StartTime time.Time `json:"start_time"`
+	OldNice   int       `json:"old_nice"`
+	NewNice   int       `json:"new_nice"`
+	Changed   time.Time `json:"changed"`
+	Reverted  bool      `json:"reverted"`
+}
+
+func priorityJournalPath(outDir string) string {
+	return filepath.Join(outDir, "priority_journal.json")
+}
+
+func loadPriorityJournal(outDir string) ([]PriorityChange, error) {
+	data, err := os.ReadFile(priorityJournalPath(outDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []PriorityChange
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func savePriorityJournal(outDir string, entries []PriorityChange) error {
+	if err := ensureDir(outDir); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(priorityJournalPath(outDir), append(data, '\n'), 0o644)
+}
+
+func runRenice(cfg Config) {
+	if simulationMode() {
+		// getNice/setNice visent le systeme: le PID du snapshot serait un vrai processus
+		fmt.Println("Non disponible en mode simulation (--fake-procs).")
+		return
+	}
+	pid, ok := readPID("PID: ")
+	if !ok {
+		return
+	}
+	proc, err := getProcess(pid)
+	if err != nil {
+		fmt.Println("Processus introuvable.")
+		return
+	}
+	old, err := getNice(pid)
+	if err != nil {
+		fmt.Printf("Erreur lecture priorite: %v\n", err)
+		return
+	}
+	fmt.Printf("Processus: %d | %s | %s | nice actuel: %d\n", proc.PID, proc.Name, proc.User, old)
+	// sans journal lisible, le changement ecraserait l'historique de restauration
+	entries, err := loadPriorityJournal(cfg.OutDir)
+	if err != nil {
+		fmt.Printf("Erreur lecture journal: %v\n", err)
+		return
+	}
+	line := readNonEmpty("Nouveau nice (-20..19): ")
+	nice, err := strconv.Atoi(line)
+	if err != nil || nice < -20 || nice > 19 {
+		fmt.Println("Valeur invalide.")
+		return
+	}
+	if !confirmAction(fmt.Sprintf("Confirmer nice %d -> %d", old, nice)) {
+		fmt.Println("Annule.")
+		return
+	}
+	if err := verifyProcessIdentity(proc); err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("RENICE ABORT pid=%d name=%s err=%v", pid, proc.Name, err))
+		return
+	}
+	if err := setNice(pid, nice); err != nil {
+		fmt.Printf("Erreur renice: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("RENICE FAIL pid=%d name=%s old=%d new=%d err=%v", pid, proc.Name, old, nice, err))
+		return
+	}
+	fmt.Println("Renice OK.")
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("RENICE OK pid=%d name=%s old=%d new=%d", pid, proc.Name, old, nice))
+
+	entries = append(entries, PriorityChange{
+		PID: pid, Name: proc.Name, StartTime: proc.StartTime,
+		OldNice: old, NewNice: nice, Changed: time.Now(),
+	})
+	if err := savePriorityJournal(cfg.OutDir, entries); err != nil {
+		fmt.Printf("Erreur ecriture journal: %v\n", err)
+	}
+}
+
+func runReniceRevert(cfg Config) {
+	if simulationMode() {
+		// getNice/setNice visent le systeme: le PID du snapshot serait un vrai processus
+		fmt.Println("Non disponible en mode simulation (--fake-procs).")
+		return
+	}
+	entries, err := loadPriorityJournal(cfg.OutDir)
+	if err != nil {
+		fmt.Printf("Erreur lecture journal: %v\n", err)
+		return
+	}
+	var pending []int
+	for i, e := range entries {
+		if e.Reverted {
+			continue
+		}
+		pending = append(pending, i)
+		fmt.Printf("%3d) PID %d | %s | nice %d -> %d | %s\n",
+			len(pending), e.PID, e.Name, e.OldNice, e.NewNice, formatTime(e.Changed))
+	}
+	if len(pending) == 0 {
+		fmt.Println("Aucun changement de priorite a restaurer.")
+		return
+	}
+	n := readIntWithDefault("Numero a restaurer (0 = annuler)", 0)
+	if n <= 0 || n > len(pending) {
+		fmt.Println("Annule.")
+		return
+	}
+	e := &entries[pending[n-1]]
+	if !confirmAction(fmt.Sprintf("Restaurer nice %d pour PID %d", e.OldNice, e.PID)) {
+		fmt.Println("Annule.")
+		return
+	}
+	target := ProcessInfo{PID: e.PID, Name: e.Name, StartTime: e.StartTime}
+	if err := verifyProcessIdentity(target); err != nil {
+		// le processus d'origine n'existe plus: rien a restaurer
+		fmt.Printf("Erreur: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("RENICE REVERT ABORT pid=%d name=%s err=%v", e.PID, e.Name, err))
+		e.Reverted = true
+		if err := savePriorityJournal(cfg.OutDir, entries); err != nil {
+			fmt.Printf("Erreur ecriture journal: %v\n", err)
+		}
+		return
+	}
+	if err := setNice(e.PID, e.OldNice); err != nil {
+		fmt.Printf("Erreur renice: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("RENICE REVERT FAIL pid=%d name=%s nice=%d err=%v", e.PID, e.Name, e.OldNice, err))
+		return
+	}
+	e.Reverted = true
+	if err := savePriorityJournal(cfg.OutDir, entries); err != nil {
+		fmt.Printf("Erreur ecriture journal: %v\n", err)
+	}
+	fmt.Println("Priorite restauree.")
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("RENICE REVERT OK pid=%d name=%s nice=%d", e.PID, e.Name, e.OldNice))
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func getNice(pid int) (int, error) {
+	return 0, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func setNice(pid, nice int) error {
+	return fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func execWithLimits(spec string, args []string) error {
+	return fmt.Errorf("limites de ressources non supportees: %s", runtime.GOOS)
+}
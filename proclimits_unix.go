@@ -0,0 +1,66 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+func getNice(pid int) (int, error) {
+	raw, err := syscall.Getpriority(syscall.PRIO_PROCESS, pid)
+	if err != nil {
+		return 0, err
+	}
+	return priorityToNice(raw), nil
+}
+
+func setNice(pid, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+}
+
+// mode --rlimits: applique les limites au processus courant puis le remplace
+// par la commande (exec), qui herite des limites et garde le meme PID
+func execWithLimits(spec string, args []string) error {
+	// sous Linux setpriority(0) ne vise que le thread appelant: il doit
+	// rester celui qui fait exec
+	runtime.LockOSThread()
+	if len(args) == 0 {
+		return fmt.Errorf("commande manquante")
+	}
+	limits, err := parseRunLimits(spec)
+	if err != nil {
+		return err
+	}
+	set := func(resource int, value uint64) error {
+		if value == 0 {
+			return nil
+		}
+		return syscall.Setrlimit(resource, &syscall.Rlimit{Cur: value, Max: value})
+	}
+	if err := set(syscall.RLIMIT_CPU, limits.CPUSeconds); err != nil {
+		return fmt.Errorf("limite cpu: %v", err)
+	}
+	if err := set(syscall.RLIMIT_AS, limits.AddressSpaceMB*1024*1024); err != nil {
+		return fmt.Errorf("limite as: %v", err)
+	}
+	if err := set(syscall.RLIMIT_NOFILE, limits.OpenFiles); err != nil {
+		return fmt.Errorf("limite nofile: %v", err)
+	}
+	if err := set(rlimitNPROC, limits.MaxProcs); err != nil {
+		return fmt.Errorf("limite nproc: %v", err)
+	}
+	if limits.SetNice {
+		if err := setNice(0, limits.Nice); err != nil {
+			return fmt.Errorf("nice: %v", err)
+		}
+	}
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(path, args, os.Environ())
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// correspondance approximative entre nice Unix et classes de priorite Windows.
+// RealTime peut affamer le systeme: High est la classe la plus haute posee,
+// RealTime n'est que lue (nice -20)
+var windowsPriorityClasses = []struct {
+	class string
+	nice  int
+}{
+	{"High", -10},
+	{"AboveNormal", -5},
+	{"Normal", 0},
+	{"BelowNormal", 10},
+	{"Idle", 19},
+}
+
+func getNice(pid int) (int, error) {
+	script := fmt.Sprintf("(Get-Process -Id %d).PriorityClass", pid)
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if err != nil {
+		return 0, err
+	}
+	class := strings.TrimSpace(string(out))
+	if strings.EqualFold(class, "RealTime") {
+		return -20, nil
+	}
+	for _, c := range windowsPriorityClasses {
+		if strings.EqualFold(c.class, class) {
+			return c.nice, nil
+		}
+	}
+	return 0, fmt.Errorf("classe de priorite inconnue: %s", class)
+}
+
+func setNice(pid, nice int) error {
+	class := windowsPriorityClasses[len(windowsPriorityClasses)-1].class
+	for _, c := range windowsPriorityClasses {
+		if nice <= c.nice {
+			class = c.class
+			break
+		}
+	}
+	script := fmt.Sprintf("(Get-Process -Id %d).PriorityClass = '%s'", pid, class)
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func execWithLimits(spec string, args []string) error {
+	return fmt.Errorf("limites de ressources non supportees sous Windows")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type liveView struct {
+	cfg      Config
+	sortKey  string
+	filter   string
+	interval time.Duration
+	procs    []ProcessInfo
+	rssDelta map[int]int64
+	rows     []ProcessInfo
+	message  string
+	updated  time.Time
+}
+
+func runLiveTop(cfg Config) {
+	secs := readIntWithDefault("Rafraichissement (secondes)", 2)
+	if secs <= 0 {
+		secs = 2
+	}
+	view := &liveView{
+		cfg:      cfg,
+		sortKey:  cfg.ProcessSort,
+		interval: time.Duration(secs) * time.Second,
+	}
+
+	prev, err := sampleProcesses(time.Duration(cfg.ProcessSampleMs) * time.Millisecond)
+	if err != nil {
+		fmt.Printf("Erreur liste: %v\n", err)
+		return
+	}
+	prevTime := time.Now()
+	view.update(prev, nil)
+	view.render()
+
+	// une seule lecture clavier en cours a la fois: le flux kill peut ensuite
+	// relire stdin sans concurrence
+	input := make(chan string)
+	readAsync := func() {
+		go func() {
+			line, err := stdinReader.ReadString('\n')
+			if err != nil && line == "" {
+				input <- "q"
+				return
+			}
+			input <- strings.TrimSpace(line)
+		}()
+	}
+	readAsync()
+
+	ticker := time.NewTicker(view.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			cur, err := listProcesses()
+			if err != nil {
+				view.message = fmt.Sprintf("Erreur liste: %v", err)
+				view.render()
+				continue
+			}
+			now := time.Now()
+			applyCPUDelta(prev, cur, now.Sub(prevTime))
+			view.update(cur, prev)
+			prev, prevTime = cur, now
+			view.render()
+		case line := <-input:
+			if line == "q" || line == "Q" {
+				return
+			}
+			if pid, ok := view.killTarget(line); ok {
+				ticker.Stop()
+				fmt.Println()
+				runKillPID(cfg, pid)
+				readLine("Entree pour revenir a la vue live...")
+				ticker.Reset(view.interval)
+			} else {
+				view.handle(line)
+			}
+			readAsync()
+			view.render()
+		}
+	}
+}
+
+func (v *liveView) update(cur, prev []ProcessInfo) {
+	v.procs = cur
+	v.updated = time.Now()
+	v.rssDelta = make(map[int]int64, len(cur))
+	if prev == nil {
+		return
+	}
+	old := make(map[int]ProcessInfo, len(prev))
+	for _, p := range prev {
+		old[p.PID] = p
+	}
+	for _, p := range cur {
+		if o, ok := old[p.PID]; ok && o.StartTime.Equal(p.StartTime) {
+			v.rssDelta[p.PID] = int64(p.RSS) - int64(o.RSS)
+		}
+	}
+}
+
+func (v *liveView) handle(line string) {
+	v.message = ""
+	switch {
+	case line == "":
+	case strings.HasPrefix(line, "/"):
+		v.filter = strings.TrimSpace(line[1:])
+	case line == "c":
+		v.sortKey = "cpu"
+	case line == "m":
+		v.sortKey = "mem"
+	case line == "t":
+		v.sortKey = "start"
+	case line == "n":
+		v.sortKey = "name"
+	case line == "p":
+		v.sortKey = "pid"
+	default:
+		v.message = "Commande inconnue: " + line
+	}
+}
+
+func (v *liveView) killTarget(line string) (int, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != "k" {
+		return 0, false
+	}
+	row, err := strconv.Atoi(fields[1])
+	if err != nil || row < 1 || row > len(v.rows) {
+		v.message = "Ligne invalide: " + fields[1]
+		return 0, false
+	}
+	return v.rows[row-1].PID, true
+}
+
+func (v *liveView) render() {
+	procs := append([]ProcessInfo(nil), v.procs...)
+	if v.filter != "" {
+		procs, _ = filterProcesses(procs, v.filter, "substring")
+	}
+	v.rows = topProcesses(procs, v.sortKey, v.cfg.ProcessTopN)
+
+	fmt.Print("\033[H\033[2J")
+	filter := v.filter
+	if filter == "" {
+		filter = "-"
+	}
+	fmt.Printf("Live %s | tri: %s | filtre: %s | %d processus | toutes les %s\n",
+		formatTime(v.updated), v.sortKey, filter, len(v.procs), v.interval)
+	fmt.Printf("%3s %7s %-12s %6s %9s %10s %-20s %s\n",
+		"#", "PID", "USER", "CPU%", "RSS", "dRSS", "NOM", "COMMANDE")
+	for i, p := range v.rows {
+		fmt.Printf("%3d %7d %-12s %6.1f %9s %10s %-20s %s\n",
+			i+1, p.PID, truncate(p.User, 12), p.CPU, formatBytes(p.RSS),
+			formatBytesDelta(v.rssDelta[p.PID]), truncate(p.Name, 20), truncate(p.Command, 50))
+	}
+	fmt.Println()
+	if v.message != "" {
+		fmt.Println(v.message)
+	}
+	fmt.Println("Commandes (+Entree): c/m/t/n/p tri | /texte filtre, / seul efface | k <ligne> kill | q quitter")
+}
+
+func formatBytesDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + formatBytes(uint64(delta))
+	case delta < 0:
+		return "-" + formatBytes(uint64(-delta))
+	default:
+		return "0"
+	}
+}
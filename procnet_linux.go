@@ -0,0 +1,234 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// lit les tables tcp/tcp6/udp/udp6/unix d'un repertoire net (/proc/net ou
+// /proc/<pid>/net) et indexe les sockets par inode
+func readSocketTables(netDir string) map[uint64]SocketInfo {
+	sockets := make(map[uint64]SocketInfo)
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		entries, err := parseInetTable(netDir+"/"+proto, proto)
+		if err != nil {
+			continue
+		}
+		for _, s := range entries {
+			sockets[s.Inode] = s
+		}
+	}
+	if entries, err := parseUnixTable(netDir + "/unix"); err == nil {
+		for _, s := range entries {
+			sockets[s.Inode] = s
+		}
+	}
+	return sockets
+}
+
+func parseInetTable(path, proto string) ([]SocketInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var out []SocketInfo
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // en-tete
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			continue
+		}
+		local, err := parseHexAddr(fields[1])
+		if err != nil {
+			continue
+		}
+		remote, err := parseHexAddr(fields[2])
+		if err != nil {
+			continue
+		}
+		state := tcpStates[fields[3]]
+		if strings.HasPrefix(proto, "udp") {
+			// pas d'etat en UDP: 07 = socket non connecte
+			state = "UNCONN"
+			if fields[3] == "01" {
+				state = "ESTABLISHED"
+			}
+		}
+		out = append(out, SocketInfo{
+			Proto:  proto,
+			Local:  local,
+			Remote: remote,
+			State:  state,
+			Inode:  inode,
+		})
+	}
+	return out, scanner.Err()
+}
+
+// adresse "IP:PORT" en hexa; l'IP est stockee par mots de 32 bits en ordre hote
+func parseHexAddr(value string) (string, error) {
+	ipHex, portHex, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", fmt.Errorf("adresse invalide: %s", value)
+	}
+	raw, err := hex.DecodeString(ipHex)
+	if err != nil || (len(raw) != 4 && len(raw) != 16) {
+		return "", fmt.Errorf("adresse invalide: %s", value)
+	}
+	for i := 0; i < len(raw); i += 4 {
+		raw[i], raw[i+1], raw[i+2], raw[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("port invalide: %s", value)
+	}
+	return net.JoinHostPort(net.IP(raw).String(), strconv.FormatUint(port, 10)), nil
+}
+
+func parseUnixTable(path string) ([]SocketInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var out []SocketInfo
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // en-tete
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[6], 10, 64)
+		if err != nil {
+			continue
+		}
+		path := ""
+		if len(fields) > 7 {
+			path = fields[7]
+		}
+		state := "UNCONN"
+		if fields[5] == "03" {
+			state = "CONNECTED"
+		} else if fields[3] == "00010000" {
+			state = "LISTEN"
+		}
+		out = append(out, SocketInfo{
+			Proto: "unix",
+			Local: path,
+			State: state,
+			Inode: inode,
+		})
+	}
+	return out, scanner.Err()
+}
+
+func socketInode(target string) (uint64, bool) {
+	if !strings.HasPrefix(target, "socket:[") || !strings.HasSuffix(target, "]") {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(target[len("socket:["):len(target)-1], 10, 64)
+	return inode, err == nil
+}
+
+func listeningSockets() ([]PortOwner, error) {
+	var sockets []SocketInfo
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		entries, err := parseInetTable("/proc/net/"+proto, proto)
+		if err != nil {
+			continue
+		}
+		for _, s := range entries {
+			if s.State == "LISTEN" || s.State == "UNCONN" {
+				sockets = append(sockets, s)
+			}
+		}
+	}
+
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	byPID := make(map[int]ProcessInfo, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+	inodes := socketOwners(procs)
+
+	owners := make([]PortOwner, 0, len(sockets))
+	for _, s := range sockets {
+		owner := PortOwner{Socket: s}
+		if pid, ok := inodes[s.Inode]; ok {
+			owner.Proc, owner.Found = byPID[pid]
+		}
+		owners = append(owners, owner)
+	}
+	sort.SliceStable(owners, func(i, j int) bool {
+		return socketPort(owners[i].Socket.Local) < socketPort(owners[j].Socket.Local)
+	})
+	return owners, nil
+}
+
+// inode de socket -> PID, en parcourant /proc/<pid>/fd (premier PID trouve)
+func socketOwners(procs []ProcessInfo) map[uint64]int {
+	owners := make(map[uint64]int)
+	for _, p := range procs {
+		fdDir := filepath.Join("/proc", strconv.Itoa(p.PID), "fd")
+		entries, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			link, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+			if err != nil {
+				continue
+			}
+			if inode, ok := socketInode(link); ok {
+				if _, seen := owners[inode]; !seen {
+					owners[inode] = p.PID
+				}
+			}
+		}
+	}
+	return owners
+}
+
+func socketPort(addr string) int {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(port)
+	return n
+}
@@ -4,71 +4,146 @@ import (
 	"bytes"
 	"encoding/csv"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ProcessInfo struct {
-	PID  int
-	Name string
+	PID        int           `json:"pid"`
+	PPID       int           `json:"ppid"`
+	Name       string        `json:"name"`
+	User       string        `json:"user"`
+	State      string        `json:"state"`
+	RSS        uint64        `json:"rss"`
+	VSZ        uint64        `json:"vsz"`
+	CPU        float64       `json:"cpu"`
+	CPUTime    time.Duration `json:"cpu_time"`
+	StartTime  time.Time     `json:"start_time"`
+	Command    string        `json:"command"`
+	Threads    int           `json:"threads"`
+	ReadBytes  uint64        `json:"read_bytes"`
+	WriteBytes uint64        `json:"write_bytes"`
 }
 
 func runProcOps(cfg Config) {
+	warnPausedProcesses(cfg.OutDir)
+	warnContainerScope()
 	for {
 		fmt.Println("=== ProcessOps ===")
 		fmt.Println("1) Lister les processus")
 		fmt.Println("2) Rechercher/filtrer")
 		fmt.Println("3) Kill securise")
+		fmt.Println("4) Arbre des processus")
+		fmt.Println("5) Kill par nom/motif")
+		fmt.Println("6) Vue live (top)")
+		fmt.Println("7) Watchdog (alertes)")
+		fmt.Println("8) Fichiers et sockets d'un PID")
+		fmt.Println("9) Processus utilisant un fichier")
+		fmt.Println("10) Ports en ecoute")
+		fmt.Println("11) Snapshot des processus (JSON/CSV)")
+		fmt.Println("12) Comparer deux snapshots")
+		fmt.Println("13) Enregistrer l'activite (serie temporelle)")
+		fmt.Println("14) Lancer / superviser une commande")
+		fmt.Println("15) Changer la priorite (renice)")
+		fmt.Println("16) Restaurer une priorite")
+		fmt.Println("17) Suspendre un processus")
+		fmt.Println("18) Processus suspendus / reprendre")
+		fmt.Println("19) Resume par utilisateur / application")
+		fmt.Println("20) Conteneurs / cgroups / namespaces")
 		fmt.Println("0) Retour")
 		choice := readLine("Choix: ")
 		switch choice {
 		case "1":
 			topN := readIntWithDefault("Top N", cfg.ProcessTopN)
-			procs, err := listProcesses(topN)
+			key := readSortKey(cfg.ProcessSort)
+			procs, err := sampleProcesses(time.Duration(cfg.ProcessSampleMs) * time.Millisecond)
 			if err != nil {
 				fmt.Printf("Erreur liste: %v\n", err)
 				break
 			}
-			printProcesses(procs)
+			printProcesses(topProcesses(procs, key, topN))
 		case "2":
+			mode := readMatchMode()
 			term := readNonEmpty("Mot de recherche: ")
-			procs, err := listProcesses(0)
+			procs, err := listProcesses()
 			if err != nil {
 				fmt.Printf("Erreur liste: %v\n", err)
 				break
 			}
-			filtered := filterProcesses(procs, term)
+			filtered, err := filterProcesses(procs, term, mode)
+			if err != nil {
+				fmt.Printf("Erreur filtre: %v\n", err)
+				break
+			}
 			printProcesses(filtered)
 		case "3":
-			pidStr := readNonEmpty("PID a tuer: ")
-			pid, err := strconv.Atoi(pidStr)
+			pid, ok := readPID("PID a tuer: ")
+			if !ok {
+				break
+			}
+			runKillPID(cfg, pid)
+		case "4":
+			procs, err := listProcesses()
 			if err != nil {
-				fmt.Println("PID invalide.")
+				fmt.Printf("Erreur liste: %v\n", err)
 				break
 			}
-			procs, _ := listProcesses(0)
-			proc, found := findProcessByPID(procs, pid)
-			procName := "unknown"
-			if found {
-				fmt.Printf("Processus: %d | %s\n", proc.PID, proc.Name)
-				procName = proc.Name
-			} else {
-				fmt.Printf("Processus: %d | (nom inconnu)\n", pid)
+			roots, byPID := buildProcessTree(procs)
+			pidStr := readLine("PID racine (vide = tout): ")
+			if pidStr == "" {
+				printProcessTree(roots)
+				break
 			}
-			if !confirmAction("Confirmer kill") {
-				fmt.Println("Annule.")
+			pid, err := strconv.Atoi(pidStr)
+			if err != nil {
+				fmt.Println("PID invalide.")
 				break
 			}
-			force := strings.ToLower(readLine("Forcer? (y/n): ")) == "y"
-			if err := killProcess(pid, force); err != nil {
-				fmt.Printf("Erreur kill: %v\n", err)
-				writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL FAIL pid=%d name=%s err=%v", pid, procName, err))
-			} else {
-				fmt.Println("Kill OK.")
-				writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL OK pid=%d name=%s force=%t", pid, procName, force))
+			node, ok := byPID[pid]
+			if !ok {
+				fmt.Println("Processus introuvable.")
+				break
 			}
+			printProcessTree([]*processNode{node})
+		case "5":
+			runKillMatching(cfg)
+		case "6":
+			runLiveTop(cfg)
+		case "7":
+			runWatchdog(cfg)
+		case "8":
+			runProcessFiles()
+		case "9":
+			runFileUsers(cfg.DefaultFile)
+		case "10":
+			runListeningPorts(cfg)
+		case "11":
+			runSnapshotExport(cfg)
+		case "12":
+			runSnapshotDiff(cfg)
+		case "13":
+			runRecord(cfg)
+		case "14":
+			runLaunch(cfg)
+		case "15":
+			runRenice(cfg)
+		case "16":
+			runReniceRevert(cfg)
+		case "17":
+			runPause(cfg)
+		case "18":
+			runResume(cfg)
+		case "19":
+			runProcessGroups(cfg)
+		case "20":
+			runContainerView()
 		case "0":
 			return
 		default:
@@ -78,94 +153,414 @@ func runProcOps(cfg Config) {
 	}
 }
 
-func listProcesses(topN int) ([]ProcessInfo, error) {
-	switch runtime.GOOS {
-	case "windows":
-		return listProcessesWindows(topN)
-	case "darwin":
-		return listProcessesDarwin(topN)
-	default:
-		return nil, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+func runKillPID(cfg Config, pid int) {
+	procs, _ := listProcesses()
+	proc, found := findProcessByPID(procs, pid)
+	if !found {
+		fmt.Println("Processus introuvable.")
+		return
+	}
+	fmt.Printf("Processus: %d | %s | %s | debut %s | %s\n",
+		proc.PID, proc.Name, proc.User, formatTime(proc.StartTime), truncate(proc.Command, 100))
+	printKillImpact(procs, pid)
+	if !confirmAction("Confirmer kill") {
+		fmt.Println("Annule.")
+		return
+	}
+	opts := readKillOptions(cfg)
+	if err := killProcess(cfg, proc, opts); err != nil {
+		fmt.Printf("Erreur kill: %v\n", err)
+	} else {
+		fmt.Println("Kill OK.")
 	}
 }
 
-func listProcessesWindows(topN int) ([]ProcessInfo, error) {
-	cmd := exec.Command("tasklist", "/FO", "CSV")
+func runKillMatching(cfg Config) {
+	mode := readMatchMode()
+	term := readNonEmpty("Nom ou motif: ")
+	procs, err := listProcesses()
+	if err != nil {
+		fmt.Printf("Erreur liste: %v\n", err)
+		return
+	}
+	targets, err := filterProcesses(procs, term, mode)
+	if err != nil {
+		fmt.Printf("Erreur filtre: %v\n", err)
+		return
+	}
+	if len(targets) == 0 {
+		fmt.Println("Aucun processus correspondant.")
+		return
+	}
+	fmt.Printf("%d processus correspondent:\n", len(targets))
+	printProcesses(targets)
+	for _, p := range targets {
+		if err := checkKillPolicy(cfg.KillDeny, p); err != nil {
+			fmt.Printf("  PID %d sera ignore: %v\n", p.PID, err)
+		}
+	}
+	if !confirmAction(fmt.Sprintf("Confirmer kill des %d processus", len(targets))) {
+		fmt.Println("Annule.")
+		return
+	}
+	opts := readKillOptions(cfg)
+	ok := 0
+	for _, p := range targets {
+		if err := killProcess(cfg, p, opts); err != nil {
+			fmt.Printf("PID %d (%s): erreur: %v\n", p.PID, p.Name, err)
+			continue
+		}
+		fmt.Printf("PID %d (%s): OK\n", p.PID, p.Name)
+		ok++
+	}
+	fmt.Printf("Resultat: %d/%d arretes.\n", ok, len(targets))
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL MATCH term=%s mode=%s sig=%s total=%d ok=%d", term, mode, opts.Signal, len(targets), ok))
+}
+
+func listProcessesWindows() ([]ProcessInfo, error) {
+	script := "Get-CimInstance Win32_Process | Select-Object ProcessId,ParentProcessId,Name," +
+		"WorkingSetSize,VirtualSize,KernelModeTime,UserModeTime,ThreadCount,ReadTransferCount,WriteTransferCount," +
+		"@{n='StartTime';e={if($_.CreationDate){$_.CreationDate.ToString('o')}}},CommandLine" +
+		" | ConvertTo-Csv -NoTypeInformation"
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
-	r := csv.NewReader(bytes.NewReader(out))
-	records, err := r.ReadAll()
+	records, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
 	if err != nil {
 		return nil, err
 	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	col := make(map[string]int)
+	for i, name := range records[0] {
+		col[strings.TrimSpace(name)] = i
+	}
+	get := func(rec []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	owners := tasklistOwners()
+	now := time.Now()
 	var procs []ProcessInfo
-	for i, rec := range records {
-		if i == 0 {
+	for _, rec := range records[1:] {
+		pid, err := strconv.Atoi(get(rec, "ProcessId"))
+		if err != nil {
 			continue
 		}
-		if len(rec) < 2 {
+		proc := ProcessInfo{
+			PID:     pid,
+			Name:    get(rec, "Name"),
+			Command: get(rec, "CommandLine"),
+		}
+		proc.PPID, _ = strconv.Atoi(get(rec, "ParentProcessId"))
+		proc.RSS, _ = strconv.ParseUint(get(rec, "WorkingSetSize"), 10, 64)
+		proc.VSZ, _ = strconv.ParseUint(get(rec, "VirtualSize"), 10, 64)
+		proc.Threads, _ = strconv.Atoi(get(rec, "ThreadCount"))
+		proc.ReadBytes, _ = strconv.ParseUint(get(rec, "ReadTransferCount"), 10, 64)
+		proc.WriteBytes, _ = strconv.ParseUint(get(rec, "WriteTransferCount"), 10, 64)
+		if start, err := time.Parse(time.RFC3339, get(rec, "StartTime")); err == nil {
+			proc.StartTime = start
+		}
+		kernel, _ := strconv.ParseUint(get(rec, "KernelModeTime"), 10, 64)
+		user, _ := strconv.ParseUint(get(rec, "UserModeTime"), 10, 64)
+		proc.CPUTime = time.Duration(kernel+user) * 100
+		proc.CPU = lifetimeCPUPercent(proc.CPUTime, proc.StartTime, now)
+		if owner, ok := owners[pid]; ok {
+			proc.User = owner.user
+			proc.State = owner.status
+		}
+		procs = append(procs, proc)
+	}
+	return procs, nil
+}
+
+type tasklistOwner struct {
+	user   string
+	status string
+}
+
+func tasklistOwners() map[int]tasklistOwner {
+	owners := make(map[int]tasklistOwner)
+	out, err := exec.Command("tasklist", "/V", "/FO", "CSV").Output()
+	if err != nil {
+		return owners
+	}
+	records, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil {
+		return owners
+	}
+	for i, rec := range records {
+		if i == 0 || len(rec) < 7 {
 			continue
 		}
 		pid, err := strconv.Atoi(strings.TrimSpace(rec[1]))
 		if err != nil {
 			continue
 		}
-		procs = append(procs, ProcessInfo{
-			PID:  pid,
-			Name: strings.TrimSpace(rec[0]),
-		})
-		if topN > 0 && len(procs) >= topN {
-			break
+		owners[pid] = tasklistOwner{
+			user:   strings.TrimSpace(rec[6]),
+			status: strings.TrimSpace(rec[5]),
 		}
 	}
-	return procs, nil
+	return owners
 }
 
-func listProcessesDarwin(topN int) ([]ProcessInfo, error) {
-	cmd := exec.Command("ps", "-Ao", "pid,comm")
+func listProcessesDarwin() ([]ProcessInfo, error) {
+	cmd := exec.Command("ps", "-Ao", "pid=,ppid=,user=,state=,rss=,vsz=,%cpu=,time=,lstart=,comm=")
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
-	lines := strings.Split(string(out), "\n")
+	commands := darwinCommandLines()
 	var procs []ProcessInfo
-	for i, line := range lines {
-		if i == 0 || strings.TrimSpace(line) == "" {
-			continue
-		}
+	for _, line := range strings.Split(string(out), "\n") {
 		fields := strings.Fields(line)
-		if len(fields) < 2 {
+		// pid ppid user state rss vsz %cpu time + lstart (5 champs) + comm
+		if len(fields) < 14 {
 			continue
 		}
 		pid, err := strconv.Atoi(fields[0])
 		if err != nil {
 			continue
 		}
-		procs = append(procs, ProcessInfo{
-			PID:  pid,
-			Name: fields[1],
-		})
-		if topN > 0 && len(procs) >= topN {
-			break
+		comm := strings.Join(fields[13:], " ")
+		proc := ProcessInfo{
+			PID:     pid,
+			Name:    filepath.Base(comm),
+			User:    fields[2],
+			State:   fields[3],
+			Command: commands[pid],
+		}
+		proc.PPID, _ = strconv.Atoi(fields[1])
+		rssKB, _ := strconv.ParseUint(fields[4], 10, 64)
+		vszKB, _ := strconv.ParseUint(fields[5], 10, 64)
+		proc.RSS = rssKB * 1024
+		proc.VSZ = vszKB * 1024
+		proc.CPU, _ = strconv.ParseFloat(strings.Replace(fields[6], ",", ".", 1), 64)
+		proc.CPUTime = parseCPUTime(fields[7])
+		lstart := strings.Join(fields[8:13], " ")
+		if start, err := time.ParseInLocation("Mon Jan 2 15:04:05 2006", lstart, time.Local); err == nil {
+			proc.StartTime = start
 		}
+		if proc.Command == "" {
+			proc.Command = comm
+		}
+		procs = append(procs, proc)
 	}
 	return procs, nil
 }
 
-func filterProcesses(procs []ProcessInfo, term string) []ProcessInfo {
-	term = strings.ToLower(strings.TrimSpace(term))
+func darwinCommandLines() map[int]string {
+	commands := make(map[int]string)
+	cmd := exec.Command("ps", "-Ao", "pid=,args=")
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		return commands
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		pidStr, args, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil {
+			continue
+		}
+		commands[pid] = strings.TrimSpace(args)
+	}
+	return commands
+}
+
+// format ps: [jj-][hh:]mm:ss[.cc]
+func parseCPUTime(value string) time.Duration {
+	var total time.Duration
+	if days, rest, ok := strings.Cut(value, "-"); ok {
+		d, err := strconv.Atoi(days)
+		if err != nil {
+			return 0
+		}
+		total += time.Duration(d) * 24 * time.Hour
+		value = rest
+	}
+	parts := strings.Split(value, ":")
+	var secs float64
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.Replace(part, ",", ".", 1), 64)
+		if err != nil {
+			return 0
+		}
+		secs = secs*60 + v
+	}
+	return total + time.Duration(secs*float64(time.Second))
+}
+
+func lifetimeCPUPercent(cpuTime time.Duration, start, now time.Time) float64 {
+	if start.IsZero() {
+		return 0
+	}
+	elapsed := now.Sub(start)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(cpuTime) / float64(elapsed) * 100
+}
+
+func sampleProcesses(interval time.Duration) ([]ProcessInfo, error) {
+	before, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return before, nil
+	}
+	start := time.Now()
+	time.Sleep(interval)
+	after, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	applyCPUDelta(before, after, time.Since(start))
+	return after, nil
+}
+
+func applyCPUDelta(before, after []ProcessInfo, elapsed time.Duration) {
+	if elapsed <= 0 {
+		return
+	}
+	prev := make(map[int]ProcessInfo, len(before))
+	for _, p := range before {
+		prev[p.PID] = p
+	}
+	for i := range after {
+		old, ok := prev[after[i].PID]
+		if !ok || !old.StartTime.Equal(after[i].StartTime) {
+			continue
+		}
+		delta := after[i].CPUTime - old.CPUTime
+		if delta < 0 {
+			delta = 0
+		}
+		after[i].CPU = float64(delta) / float64(elapsed) * 100
+	}
+}
+
+var processSortKeys = []string{"cpu", "mem", "start", "name", "pid"}
+
+func readSortKey(def string) string {
+	line := strings.ToLower(readLine(fmt.Sprintf("Tri (%s) [%s]: ", strings.Join(processSortKeys, "/"), def)))
+	if line == "" {
+		return def
+	}
+	if validSortKey(line) {
+		return line
+	}
+	fmt.Println("Cle de tri inconnue, utilisation de la valeur par defaut.")
+	return def
+}
+
+func validSortKey(key string) bool {
+	for _, k := range processSortKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+func sortProcesses(procs []ProcessInfo, key string) {
+	sort.SliceStable(procs, func(i, j int) bool {
+		a, b := procs[i], procs[j]
+		switch key {
+		case "cpu":
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU
+			}
+		case "mem":
+			if a.RSS != b.RSS {
+				return a.RSS > b.RSS
+			}
+		case "start":
+			if !a.StartTime.Equal(b.StartTime) {
+				return a.StartTime.After(b.StartTime)
+			}
+		case "name":
+			an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name)
+			if an != bn {
+				return an < bn
+			}
+		}
+		return a.PID < b.PID
+	})
+}
+
+func topProcesses(procs []ProcessInfo, key string, n int) []ProcessInfo {
+	sortProcesses(procs, key)
+	if n > 0 && n < len(procs) {
+		return procs[:n]
+	}
+	return procs
+}
+
+var matchModes = []string{"substring", "regex", "exact"}
+
+func readMatchMode() string {
+	line := strings.ToLower(readLine(fmt.Sprintf("Mode (%s) [substring]: ", strings.Join(matchModes, "/"))))
+	for _, mode := range matchModes {
+		if line == mode {
+			return mode
+		}
+	}
+	if line != "" {
+		fmt.Println("Mode inconnu, substring utilise.")
+	}
+	return "substring"
+}
+
+func filterProcesses(procs []ProcessInfo, term, mode string) ([]ProcessInfo, error) {
+	term = strings.TrimSpace(term)
 	if term == "" {
-		return procs
+		return procs, nil
+	}
+	var match func(name string) bool
+	switch mode {
+	case "regex":
+		re, err := regexp.Compile("(?i)" + term)
+		if err != nil {
+			return nil, fmt.Errorf("regex invalide: %v", err)
+		}
+		match = re.MatchString
+	case "exact":
+		match = func(name string) bool { return strings.EqualFold(name, term) }
+	default:
+		lower := strings.ToLower(term)
+		match = func(name string) bool { return strings.Contains(strings.ToLower(name), lower) }
 	}
 	var out []ProcessInfo
 	for _, p := range procs {
-		if strings.Contains(strings.ToLower(p.Name), term) {
+		if match(p.Name) {
 			out = append(out, p)
 		}
 	}
-	return out
+	return out, nil
+}
+
+func readPID(prompt string) (int, bool) {
+	pid, err := strconv.Atoi(readNonEmpty(prompt))
+	if err != nil || pid <= 0 {
+		fmt.Println("PID invalide.")
+		return 0, false
+	}
+	return pid, true
 }
 
 func findProcessByPID(procs []ProcessInfo, pid int) (ProcessInfo, bool) {
@@ -182,34 +577,127 @@ func printProcesses(procs []ProcessInfo) {
 		fmt.Println("Aucun processus.")
 		return
 	}
+	fmt.Printf("%7s %7s %-12s %-4s %6s %9s %9s %-19s %-20s %s\n",
+		"PID", "PPID", "USER", "ETAT", "CPU%", "RSS", "VSZ", "DEBUT", "NOM", "COMMANDE")
 	for _, p := range procs {
-		fmt.Printf("%d | %s\n", p.PID, p.Name)
+		fmt.Printf("%7d %7d %-12s %-4s %6.1f %9s %9s %-19s %-20s %s\n",
+			p.PID, p.PPID, truncate(p.User, 12), truncate(p.State, 4), p.CPU,
+			formatBytes(p.RSS), formatBytes(p.VSZ), formatTime(p.StartTime),
+			truncate(p.Name, 20), truncate(p.Command, 60))
 	}
 }
 
-func killProcess(pid int, force bool) error {
-	switch runtime.GOOS {
-	case "windows":
-		args := []string{"/PID", strconv.Itoa(pid), "/T"}
-		if force {
-			args = append(args, "/F")
+type KillOptions struct {
+	Signal   string
+	Escalate bool
+	Timeout  time.Duration
+}
+
+func readKillOptions(cfg Config) KillOptions {
+	opts := KillOptions{
+		Signal:  "TERM",
+		Timeout: time.Duration(cfg.KillTimeoutSec) * time.Second,
+	}
+	line := strings.ToUpper(readLine(fmt.Sprintf("Signal (%s) [TERM]: ", strings.Join(signalOrder, "/"))))
+	line = strings.TrimPrefix(line, "SIG")
+	if line != "" {
+		if signalSupported(line) {
+			opts.Signal = line
+		} else {
+			fmt.Println("Signal inconnu, TERM utilise.")
 		}
-		cmd := exec.Command("taskkill", args...)
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	switch opts.Signal {
+	case "TERM", "INT", "HUP", "QUIT":
+		prompt := fmt.Sprintf("Escalader vers KILL apres %s? (y/n): ", opts.Timeout)
+		opts.Escalate = strings.ToLower(readLine(prompt)) == "y"
+	}
+	return opts
+}
+
+func signalSupported(name string) bool {
+	for _, s := range signalOrder {
+		if s == name {
+			return true
 		}
-		return nil
-	case "darwin":
-		args := []string{strconv.Itoa(pid)}
-		if force {
-			args = []string{"-9", strconv.Itoa(pid)}
+	}
+	return false
+}
+
+func killProcess(cfg Config, proc ProcessInfo, opts KillOptions) error {
+	err := checkKillPolicy(cfg.KillDeny, proc)
+	if err == nil && runtime.GOOS == "windows" {
+		err = checkDescendantsPolicy(cfg.KillDeny, proc)
+	}
+	if err != nil {
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL DENIED pid=%d name=%s user=%s err=%v", proc.PID, proc.Name, proc.User, err))
+		return err
+	}
+	if err := verifyProcessIdentity(proc); err != nil {
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL ABORT pid=%d name=%s err=%v", proc.PID, proc.Name, err))
+		return err
+	}
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL SIGNAL pid=%d name=%s sig=%s", proc.PID, proc.Name, opts.Signal))
+	if err := sendSignal(proc.PID, opts.Signal); err != nil {
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL FAIL pid=%d name=%s sig=%s err=%v", proc.PID, proc.Name, opts.Signal, err))
+		if !opts.Escalate {
+			return err
 		}
-		cmd := exec.Command("kill", args...)
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+		// l'escalade demandee couvre aussi un premier signal refuse
+		fmt.Printf("Echec de %s (%v), envoi de KILL.\n", opts.Signal, err)
+	} else {
+		if !opts.Escalate {
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL OK pid=%d name=%s sig=%s", proc.PID, proc.Name, opts.Signal))
+			return nil
 		}
-		return nil
-	default:
-		return fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+		fmt.Printf("Attente de la fin du processus (max %s)...\n", opts.Timeout)
+		if waitProcessExit(proc.PID, opts.Timeout) {
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL OK pid=%d name=%s sig=%s exited", proc.PID, proc.Name, opts.Signal))
+			return nil
+		}
+		fmt.Println("Processus toujours actif, envoi de KILL.")
+	}
+	if err := verifyProcessIdentity(proc); err != nil {
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL ABORT pid=%d name=%s err=%v", proc.PID, proc.Name, err))
+		return err
+	}
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL ESCALATE pid=%d name=%s timeout=%s sig=KILL", proc.PID, proc.Name, opts.Timeout))
+	if err := sendSignal(proc.PID, "KILL"); err != nil {
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL FAIL pid=%d name=%s sig=KILL err=%v", proc.PID, proc.Name, err))
+		return err
+	}
+	if !waitProcessExit(proc.PID, opts.Timeout) {
+		err := fmt.Errorf("processus toujours actif apres KILL")
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL FAIL pid=%d name=%s sig=KILL err=%v", proc.PID, proc.Name, err))
+		return err
+	}
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("KILL OK pid=%d name=%s sig=KILL exited", proc.PID, proc.Name))
+	return nil
+}
+
+// le couple nom + heure de demarrage identifie le processus confirme par
+// l'utilisateur: si le PID a ete recycle entre temps, on ne signale rien
+func verifyProcessIdentity(expected ProcessInfo) error {
+	current, err := getProcess(expected.PID)
+	if err != nil {
+		return fmt.Errorf("processus %d introuvable: %v", expected.PID, err)
+	}
+	if current.Name != expected.Name || !current.StartTime.Equal(expected.StartTime) {
+		return fmt.Errorf("PID %d reutilise: %s (debut %s) au lieu de %s (debut %s)",
+			expected.PID, current.Name, formatTime(current.StartTime), expected.Name, formatTime(expected.StartTime))
+	}
+	return nil
+}
+
+func waitProcessExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if !processAlive(pid) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(200 * time.Millisecond)
 	}
 }
@@ -0,0 +1,232 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absent du paquet syscall
+const rlimitNPROC = 6
+
+// USER_HZ vaut 100 sur toutes les architectures Linux courantes
+const linuxClockTicks = 100
+
+type procLinuxContext struct {
+	bootTime time.Time
+	now      time.Time
+	pageSize uint64
+	users    map[string]string
+}
+
+func newProcLinuxContext() *procLinuxContext {
+	return &procLinuxContext{
+		bootTime: linuxBootTime(),
+		now:      time.Now(),
+		pageSize: uint64(os.Getpagesize()),
+		users:    make(map[string]string),
+	}
+}
+
+func (c *procLinuxContext) userName(uid string) string {
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	c.users[uid] = name
+	return name
+}
+
+func linuxBootTime() time.Time {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			sec, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}
+			}
+			return time.Unix(sec, 0)
+		}
+	}
+	return time.Time{}
+}
+
+func listProcessesLinux() ([]ProcessInfo, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	ctx := newProcLinuxContext()
+	var procs []ProcessInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		proc, err := ctx.readProc(pid)
+		if err != nil {
+			// processus termine entre ReadDir et la lecture
+			continue
+		}
+		procs = append(procs, proc)
+	}
+	return procs, nil
+}
+
+func getProcessLinux(pid int) (ProcessInfo, error) {
+	return newProcLinuxContext().readProc(pid)
+}
+
+func (c *procLinuxContext) readProc(pid int) (ProcessInfo, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+
+	statData, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	name, fields, err := parseProcStat(string(statData))
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	// champs 3 a 24 de proc(5), decales de 3 car pid et comm sont retires
+	if len(fields) < 22 {
+		return ProcessInfo{}, fmt.Errorf("format stat invalide")
+	}
+	proc := ProcessInfo{
+		PID:   pid,
+		State: fields[0],
+	}
+	proc.PPID, _ = strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	startTicks, _ := strconv.ParseUint(fields[19], 10, 64)
+	proc.VSZ, _ = strconv.ParseUint(fields[20], 10, 64)
+	rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+	proc.RSS = rssPages * c.pageSize
+	if !c.bootTime.IsZero() {
+		proc.StartTime = c.bootTime.Add(ticksToDuration(startTicks))
+	}
+	proc.CPUTime = ticksToDuration(utime + stime)
+	proc.CPU = lifetimeCPUPercent(proc.CPUTime, proc.StartTime, c.now)
+
+	status, err := readProcStatus(dir)
+	if err == nil {
+		if status["Name"] != "" {
+			name = status["Name"]
+		}
+		if uids := strings.Fields(status["Uid"]); len(uids) > 0 {
+			proc.User = c.userName(uids[0])
+		}
+		proc.Threads, _ = strconv.Atoi(status["Threads"])
+	}
+	// rchar/wchar: tout octet lu ou ecrit (disque, pipes, sockets), comme
+	// ReadTransferCount/WriteTransferCount sous Windows; illisible sans droits
+	if io, err := readProcKeyValues(filepath.Join(dir, "io")); err == nil {
+		proc.ReadBytes, _ = strconv.ParseUint(io["rchar"], 10, 64)
+		proc.WriteBytes, _ = strconv.ParseUint(io["wchar"], 10, 64)
+	}
+
+	// le noyau tronque le nom a 15 caracteres, argv[0] donne le nom complet
+	args := readProcCmdline(dir)
+	if len(args) > 0 && len(name) >= 15 {
+		argv0 := filepath.Base(args[0])
+		if strings.HasPrefix(argv0, name) {
+			name = argv0
+		}
+	}
+	proc.Name = name
+	if len(args) > 0 {
+		proc.Command = strings.Join(args, " ")
+	} else {
+		// thread noyau: pas de ligne de commande
+		proc.Command = "[" + name + "]"
+	}
+	return proc, nil
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / linuxClockTicks
+}
+
+// le nom peut contenir des espaces et des parentheses: on coupe sur la derniere ")"
+func parseProcStat(data string) (string, []string, error) {
+	open := strings.IndexByte(data, '(')
+	end := strings.LastIndexByte(data, ')')
+	if open < 0 || end < open {
+		return "", nil, fmt.Errorf("format stat invalide")
+	}
+	return data[open+1 : end], strings.Fields(data[end+1:]), nil
+}
+
+func readProcStatus(dir string) (map[string]string, error) {
+	return readProcKeyValues(filepath.Join(dir, "status"))
+}
+
+func readProcKeyValues(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	return values, scanner.Err()
+}
+
+func readProcCmdline(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	data = bytes.TrimRight(data, "\x00")
+	var args []string
+	for _, part := range bytes.Split(data, []byte{0}) {
+		args = append(args, string(part))
+	}
+	return args
+}
+
+func processState(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return ""
+	}
+	_, fields, err := parseProcStat(string(data))
+	if err != nil || len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// getpriority(2) renvoie 20 - nice sous Linux
+func priorityToNice(raw int) int {
+	return 20 - raw
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// valeur macOS / BSD, absente du paquet syscall
+const rlimitNPROC = 7
+
+func listProcessesLinux() ([]ProcessInfo, error) {
+	return nil, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func getProcessLinux(pid int) (ProcessInfo, error) {
+	return ProcessInfo{}, fmt.Errorf("OS non supporte: %s", runtime.GOOS)
+}
+
+func processState(pid int) string {
+	return ""
+}
+
+func priorityToNice(raw int) int {
+	return raw
+}
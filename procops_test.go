@@ -0,0 +1,269 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testStart = time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+
+// PID au-dela de pid_max sous Linux: jamais ceux du test ou de ses parents
+func testProcs() []ProcessInfo {
+	return []ProcessInfo{
+		{PID: 5000100, PPID: 1, Name: "nginx", User: "www", State: "S", StartTime: testStart},
+		{PID: 5000101, PPID: 5000100, Name: "nginx-worker", User: "www", State: "S", StartTime: testStart},
+		{PID: 5000200, PPID: 1, Name: "sshd", User: "root", State: "S", StartTime: testStart},
+		{PID: 5000300, PPID: 1, Name: "Worker.exe", User: "alice", State: "S", StartTime: testStart},
+	}
+}
+
+// remplace processBackend le temps du test
+func useFakeBackend(t *testing.T, procs []ProcessInfo) *fakeBackend {
+	t.Helper()
+	fake := newFakeBackend(procs)
+	saved := processBackend
+	processBackend = fake
+	t.Cleanup(func() { processBackend = saved })
+	return fake
+}
+
+func testKillConfig(t *testing.T) Config {
+	cfg := defaultConfig()
+	cfg.OutDir = t.TempDir()
+	return cfg
+}
+
+func readAudit(t *testing.T, cfg Config) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(cfg.OutDir, "audit.log"))
+	if err != nil {
+		t.Fatalf("lecture audit: %v", err)
+	}
+	return string(data)
+}
+
+func pidsOf(procs []ProcessInfo) []int {
+	out := make([]int, len(procs))
+	for i, p := range procs {
+		out[i] = p.PID
+	}
+	return out
+}
+
+func TestFilterProcesses(t *testing.T) {
+	tests := []struct {
+		term, mode string
+		want       []int
+	}{
+		{"", "substring", []int{5000100, 5000101, 5000200, 5000300}},
+		{"NGINX", "substring", []int{5000100, 5000101}},
+		{"^nginx$", "regex", []int{5000100}},
+		{"worker", "regex", []int{5000101, 5000300}},
+		{"worker.exe", "exact", []int{5000300}},
+		{"nginx", "exact", []int{5000100}},
+		{"absent", "substring", nil},
+	}
+	for _, tt := range tests {
+		got, err := filterProcesses(testProcs(), tt.term, tt.mode)
+		if err != nil {
+			t.Fatalf("filterProcesses(%q, %s): %v", tt.term, tt.mode, err)
+		}
+		gotPIDs := pidsOf(got)
+		if len(gotPIDs) != len(tt.want) {
+			t.Errorf("filterProcesses(%q, %s) = %v, attendu %v", tt.term, tt.mode, gotPIDs, tt.want)
+			continue
+		}
+		for i := range gotPIDs {
+			if gotPIDs[i] != tt.want[i] {
+				t.Errorf("filterProcesses(%q, %s) = %v, attendu %v", tt.term, tt.mode, gotPIDs, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestFilterProcessesBadRegex(t *testing.T) {
+	if _, err := filterProcesses(testProcs(), "nginx(", "regex"); err == nil {
+		t.Fatal("regex invalide acceptee")
+	}
+}
+
+func TestCheckKillPolicy(t *testing.T) {
+	policy := KillPolicy{
+		PIDs:  []int{5004242},
+		Names: []string{"sshd", "*.exe"},
+		Users: []string{"Root"},
+	}
+	denied := []ProcessInfo{
+		{PID: 1, Name: "init"},
+		{PID: os.Getpid(), Name: "tp"},
+		{PID: os.Getppid(), Name: "bash"},
+		{PID: 5004242, Name: "app"},
+		{PID: 5000500, Name: "SSHD"},
+		{PID: 5000501, Name: "Worker.exe"},
+		{PID: 5000502, Name: "app", User: "root"},
+	}
+	for _, p := range denied {
+		if err := checkKillPolicy(policy, p); err == nil {
+			t.Errorf("kill de %d (%s, %s) autorise", p.PID, p.Name, p.User)
+		}
+	}
+	allowed := ProcessInfo{PID: 5000503, Name: "nginx", User: "www"}
+	if err := checkKillPolicy(policy, allowed); err != nil {
+		t.Errorf("kill de nginx refuse: %v", err)
+	}
+}
+
+func TestCheckKillPolicyAncestors(t *testing.T) {
+	ancestors := toolAncestors()
+	if len(ancestors) == 0 || ancestors[0] != os.Getppid() {
+		t.Fatalf("ascendance = %v, attendu %d en tete", ancestors, os.Getppid())
+	}
+	for _, pid := range ancestors {
+		if err := checkKillPolicy(KillPolicy{}, ProcessInfo{PID: pid}); err == nil {
+			t.Errorf("kill de l'ancetre %d autorise", pid)
+		}
+	}
+}
+
+func TestCheckDescendantsPolicy(t *testing.T) {
+	procs := append(testProcs(),
+		ProcessInfo{PID: 5000400, PPID: 1, Name: "launcher", User: "alice", StartTime: testStart},
+		ProcessInfo{PID: 5000401, PPID: 5000400, Name: "helper", User: "alice", StartTime: testStart},
+		ProcessInfo{PID: 5000402, PPID: 5000401, Name: "sshd", User: "root", StartTime: testStart},
+	)
+	useFakeBackend(t, procs)
+	policy := KillPolicy{Names: []string{"sshd"}}
+	launcher, _ := getProcess(5000400)
+	if err := checkDescendantsPolicy(policy, launcher); err == nil {
+		t.Error("arbre contenant sshd autorise")
+	}
+	nginx, _ := getProcess(5000100)
+	if err := checkDescendantsPolicy(policy, nginx); err != nil {
+		t.Errorf("arbre nginx refuse: %v", err)
+	}
+}
+
+func TestKillProcessDenied(t *testing.T) {
+	fake := useFakeBackend(t, testProcs())
+	cfg := testKillConfig(t)
+	proc, _ := getProcess(5000200)
+	if err := killProcess(cfg, proc, KillOptions{Signal: "TERM"}); err == nil {
+		t.Fatal("kill de sshd autorise")
+	}
+	if len(fake.Sent) != 0 {
+		t.Errorf("signaux envoyes malgre le refus: %v", fake.Sent)
+	}
+	if !processAlive(5000200) {
+		t.Error("sshd arrete")
+	}
+	if audit := readAudit(t, cfg); !strings.Contains(audit, "KILL DENIED pid=5000200 name=sshd") {
+		t.Errorf("audit sans KILL DENIED:\n%s", audit)
+	}
+}
+
+func TestKillProcessPIDReused(t *testing.T) {
+	fake := useFakeBackend(t, testProcs())
+	cfg := testKillConfig(t)
+	// le processus confirme par l'utilisateur avait demarre plus tot
+	proc, _ := getProcess(5000100)
+	proc.StartTime = testStart.Add(-time.Hour)
+	if err := killProcess(cfg, proc, KillOptions{Signal: "TERM"}); err == nil {
+		t.Fatal("kill d'un PID recycle autorise")
+	}
+	if len(fake.Sent) != 0 {
+		t.Errorf("signaux envoyes a un PID recycle: %v", fake.Sent)
+	}
+	if audit := readAudit(t, cfg); !strings.Contains(audit, "KILL ABORT pid=5000100 name=nginx") {
+		t.Errorf("audit sans KILL ABORT:\n%s", audit)
+	}
+}
+
+func TestKillProcessTerm(t *testing.T) {
+	fake := useFakeBackend(t, testProcs())
+	cfg := testKillConfig(t)
+	proc, _ := getProcess(5000101)
+	opts := KillOptions{Signal: "TERM", Escalate: true, Timeout: 300 * time.Millisecond}
+	if err := killProcess(cfg, proc, opts); err != nil {
+		t.Fatalf("killProcess: %v", err)
+	}
+	if len(fake.Sent) != 1 || fake.Sent[0].Signal != "TERM" {
+		t.Errorf("signaux = %v, attendu TERM seul", fake.Sent)
+	}
+	audit := readAudit(t, cfg)
+	for _, want := range []string{"KILL SIGNAL pid=5000101 name=nginx-worker sig=TERM", "KILL OK pid=5000101 name=nginx-worker sig=TERM exited"} {
+		if !strings.Contains(audit, want) {
+			t.Errorf("audit sans %q:\n%s", want, audit)
+		}
+	}
+}
+
+func TestKillProcessEscalate(t *testing.T) {
+	fake := useFakeBackend(t, testProcs())
+	fake.Stubborn[5000101] = true
+	cfg := testKillConfig(t)
+	proc, _ := getProcess(5000101)
+	opts := KillOptions{Signal: "TERM", Escalate: true, Timeout: 300 * time.Millisecond}
+	if err := killProcess(cfg, proc, opts); err != nil {
+		t.Fatalf("killProcess: %v", err)
+	}
+	if processAlive(5000101) {
+		t.Error("processus toujours actif apres KILL")
+	}
+	want := []fakeSignal{{PID: 5000101, Signal: "TERM"}, {PID: 5000101, Signal: "KILL"}}
+	if len(fake.Sent) != len(want) || fake.Sent[0] != want[0] || fake.Sent[1] != want[1] {
+		t.Errorf("signaux = %v, attendu %v", fake.Sent, want)
+	}
+	audit := readAudit(t, cfg)
+	for _, want := range []string{
+		"KILL SIGNAL pid=5000101 name=nginx-worker sig=TERM",
+		"KILL ESCALATE pid=5000101 name=nginx-worker timeout=300ms sig=KILL",
+		"KILL OK pid=5000101 name=nginx-worker sig=KILL exited",
+	} {
+		if !strings.Contains(audit, want) {
+			t.Errorf("audit sans %q:\n%s", want, audit)
+		}
+	}
+}
+
+func TestKillProcessEscalateAfterFailure(t *testing.T) {
+	fake := useFakeBackend(t, testProcs())
+	cfg := testKillConfig(t)
+	proc, _ := getProcess(5000101)
+	// signal refuse par le backend: l'escalade passe directement a KILL
+	opts := KillOptions{Signal: "BOGUS", Escalate: true, Timeout: 300 * time.Millisecond}
+	if err := killProcess(cfg, proc, opts); err != nil {
+		t.Fatalf("killProcess: %v", err)
+	}
+	if len(fake.Sent) != 1 || fake.Sent[0].Signal != "KILL" {
+		t.Errorf("signaux = %v, attendu KILL seul", fake.Sent)
+	}
+	audit := readAudit(t, cfg)
+	for _, want := range []string{
+		"KILL FAIL pid=5000101 name=nginx-worker sig=BOGUS",
+		"KILL ESCALATE pid=5000101 name=nginx-worker",
+		"KILL OK pid=5000101 name=nginx-worker sig=KILL exited",
+	} {
+		if !strings.Contains(audit, want) {
+			t.Errorf("audit sans %q:\n%s", want, audit)
+		}
+	}
+}
+
+func TestKillProcessFailureWithoutEscalate(t *testing.T) {
+	fake := useFakeBackend(t, testProcs())
+	cfg := testKillConfig(t)
+	proc, _ := getProcess(5000101)
+	if err := killProcess(cfg, proc, KillOptions{Signal: "BOGUS"}); err == nil {
+		t.Fatal("echec du signal non signale")
+	}
+	if len(fake.Sent) != 0 || !processAlive(5000101) {
+		t.Errorf("KILL envoye sans escalade: %v", fake.Sent)
+	}
+	if audit := readAudit(t, cfg); !strings.Contains(audit, "KILL FAIL pid=5000101 name=nginx-worker sig=BOGUS") {
+		t.Errorf("audit sans KILL FAIL:\n%s", audit)
+	}
+}
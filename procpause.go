@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PausedProcess struct {
+	PID       int       `json:"pid"`
+	Name      string    `json:"name"`
+	StartTime time.Time `json:"start_time"`
+	PausedBy  string    `json:"paused_by"`
+	Host      string    `json:"host"`
+	PausedAt  time.Time `json:"paused_at"`
+}
+
+func pausedStatePath(outDir string) string {
+	return filepath.Join(outDir, "paused.json")
+}
+
+func loadPaused(outDir string) ([]PausedProcess, error) {
+	data, err := os.ReadFile(pausedStatePath(outDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []PausedProcess
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func savePaused(outDir string, entries []PausedProcess) error {
+	if err := ensureDir(outDir); err != nil {
+		return err
+	}
+	if entries == nil {
+		entries = []PausedProcess{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(pausedStatePath(outDir), append(data, '\n'), 0o644)
+}
+
+func currentUserName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+func warnPausedProcesses(outDir string) {
+	entries, err := loadPaused(outDir)
+	if err != nil || len(entries) == 0 {
+		return
+	}
+	fmt.Printf("Attention: %d processus suspendus par l'outil (option 18 pour les reprendre).\n", len(entries))
+}
+
+func runPause(cfg Config) {
+	pid, ok := readPID("PID a suspendre: ")
+	if !ok {
+		return
+	}
+	proc, err := getProcess(pid)
+	if err != nil {
+		fmt.Println("Processus introuvable.")
+		return
+	}
+	fmt.Printf("Processus: %d | %s | %s | debut %s\n", proc.PID, proc.Name, proc.User, formatTime(proc.StartTime))
+	// suspendre init, l'outil ou son shell bloquerait la session: meme liste que pour kill
+	if err := checkKillPolicy(cfg.KillDeny, proc); err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("PAUSE DENIED pid=%d name=%s err=%v", pid, proc.Name, err))
+		return
+	}
+	// sans journal lisible, l'entree ecrite plus bas remplacerait les
+	// suspensions deja enregistrees
+	entries, err := loadPaused(cfg.OutDir)
+	if err != nil {
+		fmt.Printf("Erreur lecture %s: %v\n", pausedStatePath(cfg.OutDir), err)
+		return
+	}
+	if !confirmAction("Confirmer suspension") {
+		fmt.Println("Annule.")
+		return
+	}
+	if err := verifyProcessIdentity(proc); err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("PAUSE ABORT pid=%d name=%s err=%v", pid, proc.Name, err))
+		return
+	}
+
+	// l'entree est ecrite avant STOP: un arret de l'outil entre les deux ne
+	// laisse pas de processus gele sans trace
+	host, _ := os.Hostname()
+	updated := append(entries, PausedProcess{
+		PID: pid, Name: proc.Name, StartTime: proc.StartTime,
+		PausedBy: currentUserName(), Host: host, PausedAt: time.Now(),
+	})
+	if err := savePaused(cfg.OutDir, updated); err != nil {
+		fmt.Printf("Erreur ecriture %s: %v\n", pausedStatePath(cfg.OutDir), err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("PAUSE FAIL pid=%d name=%s err=%v", pid, proc.Name, err))
+		return
+	}
+	if err := sendSignal(pid, "STOP"); err != nil {
+		fmt.Printf("Erreur suspension: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("PAUSE FAIL pid=%d name=%s err=%v", pid, proc.Name, err))
+		if err := savePaused(cfg.OutDir, entries); err != nil {
+			fmt.Printf("Erreur ecriture %s: %v\n", pausedStatePath(cfg.OutDir), err)
+		}
+		return
+	}
+	fmt.Println("Processus suspendu.")
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("PAUSE OK pid=%d name=%s", pid, proc.Name))
+}
+
+func runResume(cfg Config) {
+	entries, err := loadPaused(cfg.OutDir)
+	if err != nil {
+		fmt.Printf("Erreur lecture %s: %v\n", pausedStatePath(cfg.OutDir), err)
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Println("Aucun processus suspendu.")
+		return
+	}
+	for i, e := range entries {
+		status := "suspendu"
+		if err := verifyProcessIdentity(ProcessInfo{PID: e.PID, Name: e.Name, StartTime: e.StartTime}); err != nil {
+			status = "disparu"
+		}
+		fmt.Printf("%3d) PID %d | %s | par %s@%s le %s | %s\n",
+			i+1, e.PID, e.Name, e.PausedBy, e.Host, formatTime(e.PausedAt), status)
+	}
+	line := strings.ToLower(readLine("Numero a reprendre (all = tous, vide = retour): "))
+	if line == "" {
+		return
+	}
+	var selected []int
+	if line == "all" {
+		for i := range entries {
+			selected = append(selected, i)
+		}
+	} else {
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 1 || n > len(entries) {
+			fmt.Println("Numero invalide.")
+			return
+		}
+		selected = []int{n - 1}
+	}
+	if !confirmAction(fmt.Sprintf("Reprendre %d processus", len(selected))) {
+		fmt.Println("Annule.")
+		return
+	}
+
+	done := make(map[int]bool)
+	for _, i := range selected {
+		e := entries[i]
+		target := ProcessInfo{PID: e.PID, Name: e.Name, StartTime: e.StartTime}
+		if err := verifyProcessIdentity(target); err != nil {
+			// processus termine ou PID recycle: on oublie l'entree sans signaler
+			fmt.Printf("PID %d: %v (entree retiree)\n", e.PID, err)
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("RESUME STALE pid=%d name=%s err=%v", e.PID, e.Name, err))
+			done[i] = true
+			continue
+		}
+		if err := sendSignal(e.PID, "CONT"); err != nil {
+			fmt.Printf("PID %d: erreur: %v\n", e.PID, err)
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("RESUME FAIL pid=%d name=%s err=%v", e.PID, e.Name, err))
+			continue
+		}
+		fmt.Printf("PID %d (%s): repris\n", e.PID, e.Name)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("RESUME OK pid=%d name=%s paused_by=%s", e.PID, e.Name, e.PausedBy))
+		done[i] = true
+	}
+
+	var remaining []PausedProcess
+	for i, e := range entries {
+		if !done[i] {
+			remaining = append(remaining, e)
+		}
+	}
+	if err := savePaused(cfg.OutDir, remaining); err != nil {
+		fmt.Printf("Erreur ecriture %s: %v\n", pausedStatePath(cfg.OutDir), err)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+type KillPolicy struct {
+	PIDs  []int    `json:"pids"`
+	Names []string `json:"names"`
+	Users []string `json:"users"`
+}
+
+func defaultKillPolicy() KillPolicy {
+	return KillPolicy{
+		Names: []string{
+			"init", "systemd", "launchd", "kernel_task", "sshd",
+			"csrss.exe", "wininit.exe", "winlogon.exe", "lsass.exe", "services.exe",
+		},
+	}
+}
+
+// PID 1, l'outil lui-meme et toute son ascendance sont toujours proteges,
+// quelle que soit la configuration: sous go run le parent est go et le
+// shell de l'utilisateur n'arrive qu'au-dessus
+func protectedPIDs(policy KillPolicy) map[int]string {
+	pids := make(map[int]string)
+	for _, pid := range policy.PIDs {
+		pids[pid] = "PID protege par la config"
+	}
+	for _, pid := range toolAncestors() {
+		pids[pid] = "ancetre de l'outil"
+	}
+	pids[os.Getppid()] = "shell parent de l'outil"
+	pids[1] = "PID 1 (init)"
+	pids[os.Getpid()] = "processus de l'outil"
+	return pids
+}
+
+var (
+	ancestorsOnce sync.Once
+	ancestorPIDs  []int
+)
+
+// ascendance reelle de l'outil (jamais celle d'un snapshot --fake-procs),
+// lue une seule fois: elle ne change pas pendant l'execution
+func toolAncestors() []int {
+	ancestorsOnce.Do(func() {
+		ppids := make(map[int]int)
+		if procs, err := newSystemBackend().List(); err == nil {
+			for _, p := range procs {
+				ppids[p.PID] = p.PPID
+			}
+		}
+		seen := make(map[int]bool)
+		for pid := os.Getppid(); pid > 1 && !seen[pid]; pid = ppids[pid] {
+			seen[pid] = true
+			ancestorPIDs = append(ancestorPIDs, pid)
+		}
+	})
+	return ancestorPIDs
+}
+
+func checkKillPolicy(policy KillPolicy, proc ProcessInfo) error {
+	if reason, ok := protectedPIDs(policy)[proc.PID]; ok {
+		return fmt.Errorf("kill refuse: %s", reason)
+	}
+	name := strings.ToLower(proc.Name)
+	for _, pattern := range policy.Names {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return fmt.Errorf("kill refuse: nom protege (%s)", pattern)
+		}
+	}
+	for _, u := range policy.Users {
+		if u != "" && strings.EqualFold(u, proc.User) {
+			return fmt.Errorf("kill refuse: utilisateur protege (%s)", u)
+		}
+	}
+	return nil
+}
+
+// taskkill /T arrete aussi les descendants: aucun ne doit etre protege
+func checkDescendantsPolicy(policy KillPolicy, proc ProcessInfo) error {
+	procs, err := listProcesses()
+	if err != nil {
+		return fmt.Errorf("kill refuse: descendants illisibles: %v", err)
+	}
+	_, byPID := buildProcessTree(procs)
+	node, ok := byPID[proc.PID]
+	if !ok {
+		return nil
+	}
+	for _, child := range collectDescendants(node) {
+		if err := checkKillPolicy(policy, child); err != nil {
+			return fmt.Errorf("kill refuse: descendant %d (%s) protege: %v", child.PID, child.Name, strings.TrimPrefix(err.Error(), "kill refuse: "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+type PortOwner struct {
+	Socket SocketInfo
+	Proc   ProcessInfo
+	Found  bool
+}
+
+func runListeningPorts(cfg Config) {
+	port := readIntWithDefault("Port (0 = tous)", 0)
+	owners, err := listeningSockets()
+	if err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		return
+	}
+	if port > 0 {
+		owners = filterPortOwners(owners, port)
+	}
+	if len(owners) == 0 {
+		fmt.Println("Aucun socket en ecoute.")
+		return
+	}
+	printPortOwners(owners)
+
+	line := readLine("Ligne a tuer (vide = retour): ")
+	if line == "" {
+		return
+	}
+	row, err := strconv.Atoi(line)
+	if err != nil || row < 1 || row > len(owners) {
+		fmt.Println("Ligne invalide.")
+		return
+	}
+	target := owners[row-1]
+	if !target.Found {
+		fmt.Println("Processus proprietaire inconnu (droits insuffisants?).")
+		return
+	}
+	runKillPID(cfg, target.Proc.PID)
+}
+
+func filterPortOwners(owners []PortOwner, port int) []PortOwner {
+	var out []PortOwner
+	for _, o := range owners {
+		_, p, err := net.SplitHostPort(o.Socket.Local)
+		if err == nil && p == strconv.Itoa(port) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func printPortOwners(owners []PortOwner) {
+	fmt.Printf("%3s %-6s %-40s %-8s %7s %-12s %s\n", "#", "PROTO", "LOCAL", "ETAT", "PID", "USER", "NOM")
+	for i, o := range owners {
+		pid, user, name := "-", "-", "?"
+		if o.Found {
+			pid = strconv.Itoa(o.Proc.PID)
+			user = truncate(o.Proc.User, 12)
+			name = o.Proc.Name
+		}
+		fmt.Printf("%3d %-6s %-40s %-8s %7s %-12s %s\n", i+1, o.Socket.Proto, o.Socket.Local, o.Socket.State, pid, user, name)
+	}
+}
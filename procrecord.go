@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ProcessSample struct {
+	Time       time.Time `json:"time"`
+	PID        int       `json:"pid"`
+	Name       string    `json:"name"`
+	CPU        float64   `json:"cpu"`
+	RSS        uint64    `json:"rss"`
+	Threads    int       `json:"threads"`
+	ReadBytes  uint64    `json:"read_bytes"`
+	WriteBytes uint64    `json:"write_bytes"`
+	ReadRate   float64   `json:"read_bps"`
+	WriteRate  float64   `json:"write_bps"`
+}
+
+type recordTarget struct {
+	pids    map[int]bool
+	pattern string
+	mode    string
+}
+
+func (t recordTarget) selectProcs(procs []ProcessInfo) []ProcessInfo {
+	if len(t.pids) > 0 {
+		var out []ProcessInfo
+		for _, p := range procs {
+			if t.pids[p.PID] {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	out, _ := filterProcesses(procs, t.pattern, t.mode)
+	return out
+}
+
+func runRecord(cfg Config) {
+	target, ok := readRecordTarget()
+	if !ok {
+		return
+	}
+	interval := readIntWithDefault("Intervalle (secondes)", 1)
+	if interval <= 0 {
+		interval = 1
+	}
+	duration := readIntWithDefault("Duree (secondes, 0 = jusqu'a Ctrl-C)", 0)
+	format := strings.ToLower(readLine("Format (csv/jsonl) [csv]: "))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "jsonl" {
+		fmt.Println("Format invalide.")
+		return
+	}
+
+	base := filepath.Join(cfg.OutDir, "record_"+time.Now().Format("20060102_150405"))
+	path := base + "." + format
+	file, err := createFileChecked(path, "record")
+	if err != nil {
+		fmt.Printf("Erreur creation %s: %v\n", path, err)
+		return
+	}
+	defer file.Close()
+	write := newSampleWriter(file, format)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+	var deadline <-chan time.Time
+	if duration > 0 {
+		deadline = time.After(time.Duration(duration) * time.Second)
+	}
+
+	fmt.Printf("Enregistrement dans %s toutes les %ds. Ctrl-C pour arreter.\n", path, interval)
+	prev, err := listProcesses()
+	if err != nil {
+		fmt.Printf("Erreur liste: %v\n", err)
+		return
+	}
+	prevTime := time.Now()
+	samples := make(map[int][]ProcessSample)
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-stop:
+			fmt.Println()
+			break loop
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+			cur, err := listProcesses()
+			if err != nil {
+				fmt.Printf("Erreur liste: %v\n", err)
+				continue
+			}
+			now := time.Now()
+			elapsed := now.Sub(prevTime)
+			applyCPUDelta(prev, cur, elapsed)
+			old := make(map[int]ProcessInfo, len(prev))
+			for _, p := range prev {
+				old[p.PID] = p
+			}
+			selected := target.selectProcs(cur)
+			for _, p := range selected {
+				s := ProcessSample{
+					Time: now, PID: p.PID, Name: p.Name, CPU: p.CPU, RSS: p.RSS,
+					Threads: p.Threads, ReadBytes: p.ReadBytes, WriteBytes: p.WriteBytes,
+				}
+				if o, ok := old[p.PID]; ok && o.StartTime.Equal(p.StartTime) && elapsed > 0 {
+					s.ReadRate = counterRate(o.ReadBytes, p.ReadBytes, elapsed)
+					s.WriteRate = counterRate(o.WriteBytes, p.WriteBytes, elapsed)
+				}
+				if err := write(s); err != nil {
+					fmt.Printf("Erreur ecriture %s: %v\n", path, err)
+					break loop
+				}
+				samples[p.PID] = append(samples[p.PID], s)
+			}
+			prev, prevTime = cur, now
+			fmt.Printf("\r%s | %d processus suivis", formatTime(now), len(selected))
+		}
+	}
+	fmt.Println()
+	fmt.Printf("OK: %s\n", path)
+
+	lines := formatRecordSummary(samples)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	summaryPath := base + "_summary" + cfg.DefaultExt
+	if err := writeLines(summaryPath, lines); err != nil {
+		fmt.Printf("Erreur ecriture %s: %v\n", summaryPath, err)
+		return
+	}
+	fmt.Printf("OK: %s\n", summaryPath)
+}
+
+func readRecordTarget() (recordTarget, bool) {
+	line := readLine("PIDs separes par des virgules (vide = par nom): ")
+	if line != "" {
+		t := recordTarget{pids: make(map[int]bool)}
+		for _, part := range strings.Split(line, ",") {
+			pid, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || pid <= 0 {
+				fmt.Printf("PID invalide: %s\n", part)
+				return recordTarget{}, false
+			}
+			t.pids[pid] = true
+		}
+		return t, true
+	}
+	mode := readMatchMode()
+	pattern := readNonEmpty("Nom ou motif: ")
+	if _, err := filterProcesses(nil, pattern, mode); err != nil {
+		fmt.Printf("Erreur filtre: %v\n", err)
+		return recordTarget{}, false
+	}
+	return recordTarget{pattern: pattern, mode: mode}, true
+}
+
+func newSampleWriter(file *os.File, format string) func(ProcessSample) error {
+	if format == "jsonl" {
+		enc := json.NewEncoder(file)
+		return func(s ProcessSample) error {
+			return enc.Encode(s)
+		}
+	}
+	w := csv.NewWriter(file)
+	header := false
+	return func(s ProcessSample) error {
+		if !header {
+			header = true
+			if err := w.Write([]string{"time", "pid", "name", "cpu", "rss", "threads",
+				"read_bytes", "write_bytes", "read_bps", "write_bps"}); err != nil {
+				return err
+			}
+		}
+		err := w.Write([]string{
+			s.Time.Format(time.RFC3339), strconv.Itoa(s.PID), s.Name,
+			strconv.FormatFloat(s.CPU, 'f', 2, 64), strconv.FormatUint(s.RSS, 10), strconv.Itoa(s.Threads),
+			strconv.FormatUint(s.ReadBytes, 10), strconv.FormatUint(s.WriteBytes, 10),
+			strconv.FormatFloat(s.ReadRate, 'f', 0, 64), strconv.FormatFloat(s.WriteRate, 'f', 0, 64),
+		})
+		if err != nil {
+			return err
+		}
+		// flush a chaque echantillon: le fichier reste exploitable si l'outil est tue
+		w.Flush()
+		return w.Error()
+	}
+}
+
+func counterRate(before, after uint64, elapsed time.Duration) float64 {
+	if after < before {
+		return 0
+	}
+	return float64(after-before) / elapsed.Seconds()
+}
+
+type seriesStats struct {
+	Min, Avg, Max, P95 float64
+}
+
+func computeStats(values []float64) seriesStats {
+	if len(values) == 0 {
+		return seriesStats{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	// p95 par rang le plus proche
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return seriesStats{
+		Min: sorted[0],
+		Avg: sum / float64(len(sorted)),
+		Max: sorted[len(sorted)-1],
+		P95: sorted[rank],
+	}
+}
+
+func formatRecordSummary(samples map[int][]ProcessSample) []string {
+	if len(samples) == 0 {
+		return []string{"Aucun echantillon."}
+	}
+	pids := make([]int, 0, len(samples))
+	for pid := range samples {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+
+	lines := []string{"Resume (min / moy / max / p95)"}
+	for _, pid := range pids {
+		series := samples[pid]
+		var cpu, rss, threads, readRate, writeRate []float64
+		for _, s := range series {
+			cpu = append(cpu, s.CPU)
+			rss = append(rss, float64(s.RSS))
+			threads = append(threads, float64(s.Threads))
+			readRate = append(readRate, s.ReadRate)
+			writeRate = append(writeRate, s.WriteRate)
+		}
+		lines = append(lines, fmt.Sprintf("PID %d (%s) - %d echantillons", pid, series[0].Name, len(series)))
+		st := computeStats(cpu)
+		lines = append(lines, fmt.Sprintf("  CPU%%     %.1f / %.1f / %.1f / %.1f", st.Min, st.Avg, st.Max, st.P95))
+		st = computeStats(rss)
+		lines = append(lines, fmt.Sprintf("  RSS      %s / %s / %s / %s",
+			formatBytes(uint64(st.Min)), formatBytes(uint64(st.Avg)), formatBytes(uint64(st.Max)), formatBytes(uint64(st.P95))))
+		st = computeStats(threads)
+		lines = append(lines, fmt.Sprintf("  Threads  %.0f / %.1f / %.0f / %.0f", st.Min, st.Avg, st.Max, st.P95))
+		st = computeStats(readRate)
+		lines = append(lines, fmt.Sprintf("  Lecture  %s/s / %s/s / %s/s / %s/s",
+			formatBytes(uint64(st.Min)), formatBytes(uint64(st.Avg)), formatBytes(uint64(st.Max)), formatBytes(uint64(st.P95))))
+		st = computeStats(writeRate)
+		lines = append(lines, fmt.Sprintf("  Ecriture %s/s / %s/s / %s/s / %s/s",
+			formatBytes(uint64(st.Min)), formatBytes(uint64(st.Avg)), formatBytes(uint64(st.Max)), formatBytes(uint64(st.P95))))
+	}
+	return lines
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type RunSpec struct {
+	Command string
+	Args    []string
+	Env     []string
+	Dir     string
+	Timeout time.Duration
+	Limits  RunLimits
+}
+
+type RunResult struct {
+	PID        int
+	ExitCode   int
+	Duration   time.Duration
+	StdoutPath string
+	StderrPath string
+	TimedOut   bool
+	Err        error
+}
+
+const (
+	superviseMinBackoff = time.Second
+	superviseMaxBackoff = 30 * time.Second
+	// un processus reste assez longtemps en vie: le delai repart du minimum
+	superviseStableRun = time.Minute
+)
+
+func runLaunch(cfg Config) {
+	spec, ok := readRunSpec()
+	if !ok {
+		return
+	}
+	supervise := strings.ToLower(readLine("Superviser (redemarrer en cas de crash)? (y/n): ")) == "y"
+	maxRestarts := 0
+	if supervise {
+		maxRestarts = readIntWithDefault("Redemarrages max (0 = illimite)", 5)
+	}
+	if !confirmAction(fmt.Sprintf("Lancer %s", spec.String())) {
+		fmt.Println("Annule.")
+		return
+	}
+
+	// Ctrl-C est aussi recu par l'enfant (meme groupe de processus): on
+	// l'intercepte pour ne pas quitter l'outil et ne pas redemarrer
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+
+	backoff := superviseMinBackoff
+	for attempt := 1; ; attempt++ {
+		res := runChild(cfg, spec, attempt)
+		printRunResult(res)
+		if !supervise || stopRequested(stop) {
+			return
+		}
+		if res.PID == 0 {
+			// la commande n'a pas demarre (introuvable, droits...): relancer
+			// ne changerait rien
+			fmt.Println("Echec du lancement, supervision arretee.")
+			return
+		}
+		if res.Err == nil && res.ExitCode == 0 && !res.TimedOut {
+			fmt.Println("Fin normale, supervision terminee.")
+			return
+		}
+		if maxRestarts > 0 && attempt > maxRestarts {
+			fmt.Println("Nombre max de redemarrages atteint.")
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("RUN GIVEUP cmd=%s restarts=%d", spec.Command, maxRestarts))
+			return
+		}
+		if res.Duration >= superviseStableRun {
+			backoff = superviseMinBackoff
+		}
+		fmt.Printf("Redemarrage dans %s (Ctrl-C pour arreter)...\n", backoff)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("RUN RESTART cmd=%s attempt=%d backoff=%s last_exit=%d", spec.Command, attempt+1, backoff, res.ExitCode))
+		select {
+		case <-stop:
+			fmt.Println("Supervision arretee.")
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("RUN STOP cmd=%s", spec.Command))
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > superviseMaxBackoff {
+			backoff = superviseMaxBackoff
+		}
+	}
+}
+
+func stopRequested(stop chan os.Signal) bool {
+	select {
+	case <-stop:
+		fmt.Println("Supervision arretee.")
+		return true
+	default:
+		return false
+	}
+}
+
+func readRunSpec() (RunSpec, bool) {
+	args, err := splitCommandLine(readNonEmpty("Commande et arguments: "))
+	if err != nil || len(args) == 0 {
+		fmt.Printf("Commande invalide: %v\n", err)
+		return RunSpec{}, false
+	}
+	spec := RunSpec{Command: args[0], Args: args[1:]}
+	env, err := splitCommandLine(readLine("Variables d'environnement (KEY=VAL ..., vide = aucune): "))
+	if err != nil {
+		fmt.Printf("Variables invalides: %v\n", err)
+		return RunSpec{}, false
+	}
+	for _, kv := range env {
+		if !strings.Contains(kv, "=") {
+			fmt.Printf("Variable invalide: %s\n", kv)
+			return RunSpec{}, false
+		}
+	}
+	spec.Env = env
+	spec.Dir = askPath("Repertoire de travail (vide = courant)", "")
+	if spec.Dir != "" && !dirExists(spec.Dir) {
+		fmt.Println("Repertoire introuvable ou non valide.")
+		return RunSpec{}, false
+	}
+	spec.Timeout = time.Duration(readIntWithDefault("Timeout (secondes, 0 = aucun)", 0)) * time.Second
+	limits, err := parseRunLimits(readLine("Limites (ex: cpu=60,as=512,nofile=256,nproc=50,nice=10; vide = aucune): "))
+	if err != nil {
+		fmt.Printf("Limites invalides: %v\n", err)
+		return RunSpec{}, false
+	}
+	// ailleurs le relais --rlimits sortirait en 126 a chaque relance
+	if limits.active() && runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		fmt.Printf("Limites de ressources non supportees sous %s.\n", runtime.GOOS)
+		return RunSpec{}, false
+	}
+	spec.Limits = limits
+	return spec, true
+}
+
+func (s RunSpec) String() string {
+	line := strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+	if s.Limits.active() {
+		line += " [" + s.Limits.String() + "]"
+	}
+	return line
+}
+
+func runChild(cfg Config, spec RunSpec, attempt int) RunResult {
+	base := fmt.Sprintf("run_%s_%s_%d", sanitizeFileName(filepath.Base(spec.Command)), time.Now().Format("20060102_150405"), attempt)
+	res := RunResult{
+		StdoutPath: filepath.Join(cfg.OutDir, base+".out"),
+		StderrPath: filepath.Join(cfg.OutDir, base+".err"),
+		ExitCode:   -1,
+	}
+	stdout, err := createFileChecked(res.StdoutPath, "run")
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer stdout.Close()
+	stderr, err := createFileChecked(res.StderrPath, "run")
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer stderr.Close()
+
+	ctx := context.Background()
+	if spec.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, spec.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, spec.Command, spec.Args...)
+	if spec.Limits.active() {
+		// les limites sont posees par l'outil relance en mode --rlimits, qui
+		// fait ensuite exec de la commande
+		exe, err := os.Executable()
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		args := append([]string{"--rlimits", spec.Limits.String(), "--", spec.Command}, spec.Args...)
+		cmd = exec.CommandContext(ctx, exe, args...)
+	}
+	cmd.Dir = spec.Dir
+	cmd.Env = append(os.Environ(), spec.Env...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		res.Err = err
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("RUN FAIL cmd=%s attempt=%d err=%v", spec.String(), attempt, err))
+		return res
+	}
+	res.PID = cmd.Process.Pid
+	fmt.Printf("Lance: PID %d (tentative %d)\n", res.PID, attempt)
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("RUN START cmd=%s pid=%d attempt=%d dir=%s", spec.String(), res.PID, attempt, spec.Dir))
+
+	err = cmd.Wait()
+	res.Duration = time.Since(start)
+	res.TimedOut = ctx.Err() == context.DeadlineExceeded
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		res.ExitCode = 0
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	default:
+		res.Err = err
+	}
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("RUN EXIT cmd=%s pid=%d code=%d duration=%s timeout=%t",
+		spec.String(), res.PID, res.ExitCode, res.Duration.Round(time.Millisecond), res.TimedOut))
+	return res
+}
+
+func printRunResult(res RunResult) {
+	if res.Err != nil {
+		fmt.Printf("Erreur: %v\n", res.Err)
+	}
+	if res.PID == 0 {
+		return
+	}
+	// -1: tue par un signal (ou par le timeout)
+	fmt.Printf("PID %d termine: code %d en %s", res.PID, res.ExitCode, res.Duration.Round(time.Millisecond))
+	if res.TimedOut {
+		fmt.Print(" (timeout)")
+	}
+	fmt.Println()
+	fmt.Printf("stdout: %s\n", res.StdoutPath)
+	fmt.Printf("stderr: %s\n", res.StderrPath)
+}
+
+// decoupe une ligne de commande en respectant les guillemets simples et doubles
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("guillemet non ferme")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
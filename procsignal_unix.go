@@ -0,0 +1,42 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+var processSignals = map[string]syscall.Signal{
+	"TERM": syscall.SIGTERM,
+	"INT":  syscall.SIGINT,
+	"HUP":  syscall.SIGHUP,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"STOP": syscall.SIGSTOP,
+	"CONT": syscall.SIGCONT,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+}
+
+var signalOrder = []string{"TERM", "INT", "HUP", "QUIT", "KILL", "STOP", "CONT", "USR1", "USR2"}
+
+// envoi de signaux commun aux backends linux et darwin
+type osSignals struct{}
+
+func (osSignals) Signal(pid int, name string) error {
+	sig, ok := processSignals[name]
+	if !ok {
+		return fmt.Errorf("signal inconnu: %s", name)
+	}
+	return syscall.Kill(pid, sig)
+}
+
+func (osSignals) Alive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	if err != nil && err != syscall.EPERM {
+		return false
+	}
+	// un zombie a deja termine, il attend seulement que son parent le recolte
+	return processState(pid) != "Z"
+}
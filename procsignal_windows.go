@@ -0,0 +1,39 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// taskkill ne connait que l'arret normal et l'arret force (/F)
+var signalOrder = []string{"TERM", "KILL"}
+
+type osSignals struct{}
+
+func (osSignals) Signal(pid int, name string) error {
+	args := []string{"/PID", strconv.Itoa(pid), "/T"}
+	switch name {
+	case "TERM":
+	case "KILL":
+		args = append(args, "/F")
+	default:
+		return fmt.Errorf("signal non supporte sous Windows: %s", name)
+	}
+	cmd := exec.Command("taskkill", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (osSignals) Alive(pid int) bool {
+	out, err := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/FO", "CSV", "/NH").Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(out), fmt.Sprintf("\"%d\"", pid))
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ProcessSnapshot struct {
+	Taken     time.Time     `json:"taken"`
+	Host      string        `json:"host"`
+	Processes []ProcessInfo `json:"processes"`
+}
+
+var snapshotCSVHeader = []string{
+	"taken", "host", "pid", "ppid", "name", "user", "state", "rss", "vsz",
+	"cpu", "cpu_time_ms", "start_time", "command", "threads", "read_bytes", "write_bytes",
+}
+
+// colonnes presentes depuis la premiere version du format CSV
+const snapshotCSVBaseColumns = 13
+
+func runSnapshotExport(cfg Config) {
+	format := strings.ToLower(readLine("Format (json/csv) [json]: "))
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		fmt.Println("Format invalide.")
+		return
+	}
+	procs, err := sampleProcesses(time.Duration(cfg.ProcessSampleMs) * time.Millisecond)
+	if err != nil {
+		fmt.Printf("Erreur liste: %v\n", err)
+		return
+	}
+	host, _ := os.Hostname()
+	snap := ProcessSnapshot{Taken: time.Now(), Host: host, Processes: procs}
+	sortProcesses(snap.Processes, "pid")
+
+	path := filepath.Join(cfg.OutDir, "procs_"+snap.Taken.Format("20060102_150405")+"."+format)
+	if format == "csv" {
+		err = writeSnapshotCSV(path, snap)
+	} else {
+		err = writeSnapshotJSON(path, snap)
+	}
+	if err != nil {
+		fmt.Printf("Erreur ecriture %s: %v\n", path, err)
+		return
+	}
+	fmt.Printf("OK: %s (%d processus)\n", path, len(procs))
+}
+
+func writeSnapshotJSON(path string, snap ProcessSnapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileChecked(path, append(data, '\n'), "snapshot")
+}
+
+func writeSnapshotCSV(path string, snap ProcessSnapshot) error {
+	file, err := createFileChecked(path, "snapshot")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	w := csv.NewWriter(file)
+	if err := w.Write(snapshotCSVHeader); err != nil {
+		return err
+	}
+	taken := snap.Taken.Format(time.RFC3339)
+	for _, p := range snap.Processes {
+		start := ""
+		if !p.StartTime.IsZero() {
+			start = p.StartTime.Format(time.RFC3339)
+		}
+		rec := []string{
+			taken, snap.Host, strconv.Itoa(p.PID), strconv.Itoa(p.PPID), p.Name, p.User, p.State,
+			strconv.FormatUint(p.RSS, 10), strconv.FormatUint(p.VSZ, 10),
+			strconv.FormatFloat(p.CPU, 'f', 2, 64), strconv.FormatInt(p.CPUTime.Milliseconds(), 10),
+			start, p.Command, strconv.Itoa(p.Threads),
+			strconv.FormatUint(p.ReadBytes, 10), strconv.FormatUint(p.WriteBytes, 10),
+		}
+		if err := w.Write(rec); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func loadSnapshot(path string) (ProcessSnapshot, error) {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return loadSnapshotCSV(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ProcessSnapshot{}, err
+	}
+	var snap ProcessSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return ProcessSnapshot{}, err
+	}
+	return snap, nil
+}
+
+func loadSnapshotCSV(path string) (ProcessSnapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ProcessSnapshot{}, err
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return ProcessSnapshot{}, err
+	}
+	var snap ProcessSnapshot
+	for i, rec := range records {
+		if i == 0 || len(rec) < snapshotCSVBaseColumns {
+			continue
+		}
+		if snap.Taken.IsZero() {
+			snap.Taken, _ = time.Parse(time.RFC3339, rec[0])
+			snap.Host = rec[1]
+		}
+		p := ProcessInfo{Name: rec[4], User: rec[5], State: rec[6], Command: rec[12]}
+		p.PID, _ = strconv.Atoi(rec[2])
+		p.PPID, _ = strconv.Atoi(rec[3])
+		p.RSS, _ = strconv.ParseUint(rec[7], 10, 64)
+		p.VSZ, _ = strconv.ParseUint(rec[8], 10, 64)
+		p.CPU, _ = strconv.ParseFloat(rec[9], 64)
+		ms, _ := strconv.ParseInt(rec[10], 10, 64)
+		p.CPUTime = time.Duration(ms) * time.Millisecond
+		p.StartTime, _ = time.Parse(time.RFC3339, rec[11])
+		if len(rec) >= len(snapshotCSVHeader) {
+			p.Threads, _ = strconv.Atoi(rec[13])
+			p.ReadBytes, _ = strconv.ParseUint(rec[14], 10, 64)
+			p.WriteBytes, _ = strconv.ParseUint(rec[15], 10, 64)
+		}
+		snap.Processes = append(snap.Processes, p)
+	}
+	return snap, nil
+}
+
+func listSnapshots(outDir string) []string {
+	var files []string
+	for _, pattern := range []string{"procs_*.json", "procs_*.csv"} {
+		matches, _ := filepath.Glob(filepath.Join(outDir, pattern))
+		files = append(files, matches...)
+	}
+	// le nom contient l'horodatage: l'ordre alphabetique est chronologique
+	sort.Slice(files, func(i, j int) bool {
+		return filepath.Base(files[i]) < filepath.Base(files[j])
+	})
+	return files
+}
+
+type snapshotChange struct {
+	Before ProcessInfo
+	After  ProcessInfo
+}
+
+type snapshotDiff struct {
+	Added   []ProcessInfo
+	Exited  []ProcessInfo
+	Changed []snapshotChange
+}
+
+// un processus est identifie par PID + heure de demarrage: un PID recycle
+// entre les deux snapshots compte comme sortie + nouveau processus
+func diffSnapshots(before, after ProcessSnapshot, memPct, cpuPts float64) snapshotDiff {
+	key := func(p ProcessInfo) string {
+		return fmt.Sprintf("%d@%d", p.PID, p.StartTime.Unix())
+	}
+	old := make(map[string]ProcessInfo, len(before.Processes))
+	for _, p := range before.Processes {
+		old[key(p)] = p
+	}
+	var diff snapshotDiff
+	seen := make(map[string]bool)
+	for _, p := range after.Processes {
+		k := key(p)
+		o, ok := old[k]
+		if !ok {
+			diff.Added = append(diff.Added, p)
+			continue
+		}
+		seen[k] = true
+		if rssChanged(o.RSS, p.RSS, memPct) || math.Abs(p.CPU-o.CPU) >= cpuPts {
+			diff.Changed = append(diff.Changed, snapshotChange{Before: o, After: p})
+		}
+	}
+	for _, p := range before.Processes {
+		if !seen[key(p)] {
+			diff.Exited = append(diff.Exited, p)
+		}
+	}
+	return diff
+}
+
+func rssChanged(before, after uint64, pct float64) bool {
+	if before == 0 {
+		return after > 0
+	}
+	change := math.Abs(float64(after)-float64(before)) / float64(before) * 100
+	return change >= pct
+}
+
+func runSnapshotDiff(cfg Config) {
+	files := listSnapshots(cfg.OutDir)
+	defBefore, defAfter := "", ""
+	if len(files) >= 2 {
+		defBefore, defAfter = files[len(files)-2], files[len(files)-1]
+	}
+	beforePath := askPath("Snapshot avant", defBefore)
+	afterPath := askPath("Snapshot apres", defAfter)
+	before, err := loadSnapshot(beforePath)
+	if err != nil {
+		fmt.Printf("Erreur lecture %s: %v\n", beforePath, err)
+		return
+	}
+	after, err := loadSnapshot(afterPath)
+	if err != nil {
+		fmt.Printf("Erreur lecture %s: %v\n", afterPath, err)
+		return
+	}
+	memPct := readIntWithDefault("Seuil variation memoire (%)", 20)
+	cpuPts := readIntWithDefault("Seuil variation CPU (points)", 10)
+
+	diff := diffSnapshots(before, after, float64(memPct), float64(cpuPts))
+	lines := formatSnapshotDiff(before, after, diff)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	outPath := filepath.Join(cfg.OutDir, "procs_diff"+cfg.DefaultExt)
+	if err := writeLines(outPath, lines); err != nil {
+		fmt.Printf("Erreur ecriture %s: %v\n", outPath, err)
+		return
+	}
+	fmt.Printf("OK: %s\n", outPath)
+}
+
+func formatSnapshotDiff(before, after ProcessSnapshot, diff snapshotDiff) []string {
+	lines := []string{
+		fmt.Sprintf("Avant: %s (%s, %d processus)", formatTime(before.Taken), before.Host, len(before.Processes)),
+		fmt.Sprintf("Apres: %s (%s, %d processus)", formatTime(after.Taken), after.Host, len(after.Processes)),
+		"",
+		fmt.Sprintf("Nouveaux: %d", len(diff.Added)),
+	}
+	for _, p := range diff.Added {
+		lines = append(lines, fmt.Sprintf("+ %7d %-20s %-12s rss=%s cpu=%.1f %s",
+			p.PID, truncate(p.Name, 20), truncate(p.User, 12), formatBytes(p.RSS), p.CPU, truncate(p.Command, 60)))
+	}
+	lines = append(lines, "", fmt.Sprintf("Termines: %d", len(diff.Exited)))
+	for _, p := range diff.Exited {
+		lines = append(lines, fmt.Sprintf("- %7d %-20s %-12s rss=%s cpu=%.1f %s",
+			p.PID, truncate(p.Name, 20), truncate(p.User, 12), formatBytes(p.RSS), p.CPU, truncate(p.Command, 60)))
+	}
+	lines = append(lines, "", fmt.Sprintf("Variations: %d", len(diff.Changed)))
+	for _, c := range diff.Changed {
+		lines = append(lines, fmt.Sprintf("~ %7d %-20s rss %s -> %s (%s) cpu %.1f -> %.1f",
+			c.After.PID, truncate(c.After.Name, 20), formatBytes(c.Before.RSS), formatBytes(c.After.RSS),
+			formatBytesDelta(int64(c.After.RSS)-int64(c.Before.RSS)), c.Before.CPU, c.After.CPU))
+	}
+	return lines
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type processNode struct {
+	Proc     ProcessInfo
+	Children []*processNode
+}
+
+func buildProcessTree(procs []ProcessInfo) ([]*processNode, map[int]*processNode) {
+	byPID := make(map[int]*processNode, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = &processNode{Proc: p}
+	}
+	// un parent demarre apres l'enfant porte un PID recycle (frequent sous
+	// Windows): ce n'est pas le vrai parent
+	parentOf := make(map[int]int, len(procs))
+	for _, p := range procs {
+		parent, ok := byPID[p.PPID]
+		if ok && p.PPID != p.PID && !parent.Proc.StartTime.After(p.StartTime) {
+			parentOf[p.PID] = p.PPID
+		}
+	}
+	// les cycles restants (heures de debut egales ou inconnues) sont coupes:
+	// le processus qui se retrouve dans sa propre ascendance devient racine
+	pids := make([]int, 0, len(byPID))
+	for pid := range byPID {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	for _, pid := range pids {
+		seen := map[int]bool{pid: true}
+		for cur := pid; ; {
+			parent, ok := parentOf[cur]
+			if !ok {
+				break
+			}
+			if parent == pid {
+				delete(parentOf, pid)
+				break
+			}
+			if seen[parent] {
+				break
+			}
+			seen[parent] = true
+			cur = parent
+		}
+	}
+
+	var roots []*processNode
+	for _, pid := range pids {
+		node := byPID[pid]
+		if parent, ok := parentOf[pid]; ok {
+			byPID[parent].Children = append(byPID[parent].Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	sortNodes(roots)
+	for _, node := range byPID {
+		sortNodes(node.Children)
+	}
+	return roots, byPID
+}
+
+func sortNodes(nodes []*processNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Proc.PID < nodes[j].Proc.PID
+	})
+}
+
+// les parcours gardent les noeuds vus: un arbre mal forme ne boucle pas
+func collectDescendants(node *processNode) []ProcessInfo {
+	return appendDescendants(nil, node, map[*processNode]bool{node: true})
+}
+
+func appendDescendants(out []ProcessInfo, node *processNode, visited map[*processNode]bool) []ProcessInfo {
+	for _, child := range node.Children {
+		if visited[child] {
+			continue
+		}
+		visited[child] = true
+		out = append(out, child.Proc)
+		out = appendDescendants(out, child, visited)
+	}
+	return out
+}
+
+// deux sous-arbres sont identiques s'ils ont les memes noms a chaque niveau
+func treeSignature(node *processNode, visited map[*processNode]bool) string {
+	if len(node.Children) == 0 || visited[node] {
+		return node.Proc.Name
+	}
+	visited[node] = true
+	defer delete(visited, node)
+	sigs := make([]string, len(node.Children))
+	for i, child := range node.Children {
+		sigs[i] = treeSignature(child, visited)
+	}
+	sort.Strings(sigs)
+	return node.Proc.Name + "(" + strings.Join(sigs, ",") + ")"
+}
+
+type nodeGroup struct {
+	node  *processNode
+	count int
+}
+
+func groupIdenticalChildren(children []*processNode) []nodeGroup {
+	var groups []nodeGroup
+	index := make(map[string]int)
+	for _, child := range children {
+		sig := treeSignature(child, make(map[*processNode]bool))
+		if i, ok := index[sig]; ok {
+			groups[i].count++
+			continue
+		}
+		index[sig] = len(groups)
+		groups = append(groups, nodeGroup{node: child, count: 1})
+	}
+	return groups
+}
+
+func printProcessTree(roots []*processNode) {
+	if len(roots) == 0 {
+		fmt.Println("Aucun processus.")
+		return
+	}
+	for _, root := range roots {
+		fmt.Println(nodeLabel(root, 1))
+		printTreeChildren(root, "", map[*processNode]bool{root: true})
+	}
+}
+
+func printTreeChildren(node *processNode, prefix string, visited map[*processNode]bool) {
+	groups := groupIdenticalChildren(node.Children)
+	for i, g := range groups {
+		if visited[g.node] {
+			continue
+		}
+		visited[g.node] = true
+		branch, next := "|- ", "|  "
+		if i == len(groups)-1 {
+			branch, next = "`- ", "   "
+		}
+		fmt.Println(prefix + branch + nodeLabel(g.node, g.count))
+		printTreeChildren(g.node, prefix+next, visited)
+	}
+}
+
+func nodeLabel(node *processNode, count int) string {
+	if count > 1 {
+		return fmt.Sprintf("%d*[%s]", count, node.Proc.Name)
+	}
+	return fmt.Sprintf("%s(%d)", node.Proc.Name, node.Proc.PID)
+}
+
+func printKillImpact(procs []ProcessInfo, pid int) {
+	_, byPID := buildProcessTree(procs)
+	node, ok := byPID[pid]
+	if !ok || len(node.Children) == 0 {
+		return
+	}
+	descendants := collectDescendants(node)
+	if runtime.GOOS == "windows" {
+		// taskkill est appele avec /T: tout l'arbre est arrete
+		fmt.Printf("Attention: %d processus descendants seront aussi arretes:\n", len(descendants))
+	} else {
+		fmt.Printf("%d processus descendants (non arretes, ils seront rattaches a un autre parent):\n", len(descendants))
+	}
+	printProcessTree([]*processNode{node})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+)
+
+type WatchdogConfig struct {
+	IntervalSec int            `json:"interval_sec"`
+	Rules       []WatchdogRule `json:"rules"`
+}
+
+type WatchdogRule struct {
+	Name         string  `json:"name"`
+	Match        string  `json:"match"`
+	PID          int     `json:"pid"`
+	MaxCPU       float64 `json:"max_cpu"`
+	MaxRSSMB     uint64  `json:"max_rss_mb"`
+	AlertMissing bool    `json:"alert_missing"`
+	AlertZombie  bool    `json:"alert_zombie"`
+}
+
+func defaultWatchdogConfig() WatchdogConfig {
+	return WatchdogConfig{IntervalSec: 5}
+}
+
+func (r WatchdogRule) label() string {
+	if r.PID > 0 {
+		return fmt.Sprintf("pid:%d", r.PID)
+	}
+	return r.Name
+}
+
+func (r WatchdogRule) matches(procs []ProcessInfo) ([]ProcessInfo, error) {
+	if r.PID > 0 {
+		if p, ok := findProcessByPID(procs, r.PID); ok {
+			return []ProcessInfo{p}, nil
+		}
+		return nil, nil
+	}
+	mode := r.Match
+	if mode == "" {
+		mode = "exact"
+	}
+	return filterProcesses(procs, r.Name, mode)
+}
+
+// conditions actives par cle: une alerte n'est emise qu'au changement d'etat
+type watchdog struct {
+	cfg    Config
+	active map[string]string
+}
+
+func runWatchdog(cfg Config) {
+	rules := cfg.Watchdog.Rules
+	if len(rules) == 0 {
+		fmt.Println("Aucune regle watchdog dans la config (section \"watchdog\").")
+		return
+	}
+	interval := time.Duration(cfg.Watchdog.IntervalSec) * time.Second
+	fmt.Printf("Watchdog: %d regle(s), controle toutes les %s. Ctrl-C pour arreter.\n", len(rules), interval)
+	for _, r := range rules {
+		fmt.Printf("- %s cpu>%.1f rss>%dMB absent=%t zombie=%t\n", r.label(), r.MaxCPU, r.MaxRSSMB, r.AlertMissing, r.AlertZombie)
+	}
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("WATCHDOG START rules=%d interval=%s", len(rules), interval))
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+
+	w := &watchdog{cfg: cfg, active: make(map[string]string)}
+	prev, err := listProcesses()
+	if err != nil {
+		fmt.Printf("Erreur liste: %v\n", err)
+		return
+	}
+	prevTime := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			fmt.Println()
+			fmt.Println("Watchdog arrete.")
+			writeAuditLog(cfg.OutDir, "WATCHDOG STOP")
+			return
+		case <-ticker.C:
+			cur, err := listProcesses()
+			if err != nil {
+				fmt.Printf("Erreur liste: %v\n", err)
+				continue
+			}
+			now := time.Now()
+			applyCPUDelta(prev, cur, now.Sub(prevTime))
+			prev, prevTime = cur, now
+			w.check(cur)
+		}
+	}
+}
+
+func (w *watchdog) check(procs []ProcessInfo) {
+	seen := make(map[string]string)
+	for _, rule := range w.cfg.Watchdog.Rules {
+		matched, err := rule.matches(procs)
+		if err != nil {
+			seen["error:"+rule.label()] = fmt.Sprintf("rule=%s err=%v", rule.label(), err)
+			continue
+		}
+		if rule.AlertMissing && len(matched) == 0 {
+			seen["missing:"+rule.label()] = fmt.Sprintf("rule=%s absent", rule.label())
+		}
+		for _, p := range matched {
+			if rule.MaxCPU > 0 && p.CPU > rule.MaxCPU {
+				seen[fmt.Sprintf("cpu:%s:%d", rule.label(), p.PID)] = fmt.Sprintf("rule=%s pid=%d name=%s cpu=%.1f>%.1f",
+					rule.label(), p.PID, p.Name, p.CPU, rule.MaxCPU)
+			}
+			if rule.MaxRSSMB > 0 && p.RSS > rule.MaxRSSMB*1024*1024 {
+				seen[fmt.Sprintf("rss:%s:%d", rule.label(), p.PID)] = fmt.Sprintf("rule=%s pid=%d name=%s rss=%s>%dMB",
+					rule.label(), p.PID, p.Name, formatBytes(p.RSS), rule.MaxRSSMB)
+			}
+			if rule.AlertZombie && p.State == "Z" {
+				seen[fmt.Sprintf("zombie:%s:%d", rule.label(), p.PID)] = fmt.Sprintf("rule=%s pid=%d name=%s zombie",
+					rule.label(), p.PID, p.Name)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := w.active[key]; !ok {
+			w.emit("ALERT", seen[key])
+		}
+	}
+	for key, msg := range w.active {
+		if _, ok := seen[key]; !ok {
+			w.emit("RECOVER", msg)
+		}
+	}
+	w.active = seen
+}
+
+func (w *watchdog) emit(kind, msg string) {
+	line := fmt.Sprintf("WATCHDOG %s %s", kind, msg)
+	fmt.Printf("%s | %s\n", formatTime(time.Now()), line)
+	writeAuditLog(w.cfg.OutDir, line)
+}
@@ -7,14 +7,19 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 func runSecureOps(cfg Config) {
+	migrateLegacyLocks(cfg.OutDir, cfg.BaseDir)
 	for {
 		fmt.Println("=== SecureOps ===")
 		fmt.Println("1) Verrouiller un fichier")
 		fmt.Println("2) Deverrouiller un fichier")
 		fmt.Println("3) Rendre un fichier read-only")
+		fmt.Println("4) Etat d'un verrou")
+		fmt.Println("5) Lister les verrous")
+		fmt.Println("6) Restaurer des permissions")
 		fmt.Println("0) Retour")
 		choice := readLine("Choix: ")
 		switch choice {
@@ -24,18 +29,25 @@ func runSecureOps(cfg Config) {
 				fmt.Println("Fichier introuvable ou non valide.")
 				break
 			}
+			if lockPath := lockPathForFile(path, cfg.OutDir); fileExists(lockPath) && !breakStaleLock(cfg, lockPath) {
+				break
+			}
+			warnFileUsers(path)
+			reason := readLine("Raison (optionnelle): ")
+			ttl := readIntWithDefault("TTL en minutes (0 = aucun)", 0)
 			if !confirmAction("Confirmer verrouillage") {
 				fmt.Println("Annule.")
 				break
 			}
-			lockPath, err := createLock(path, cfg.OutDir)
+			lockPath, info, err := createLock(path, cfg.OutDir, reason, ttl)
 			if err != nil {
 				fmt.Printf("Erreur lock: %v\n", err)
 				writeAuditLog(cfg.OutDir, fmt.Sprintf("LOCK FAIL file=%s err=%v", path, err))
 				break
 			}
-			fmt.Printf("Lock cree: %s\n", lockPath)
-			writeAuditLog(cfg.OutDir, fmt.Sprintf("LOCK OK file=%s lock=%s", path, lockPath))
+			fmt.Printf("Lock cree: %s (detenteur PID %d)\n", lockPath, info.PID)
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("LOCK OK file=%s lock=%s %s ttl_min=%d reason=%q",
+				path, lockPath, info.auditFields(), info.TTLMin, info.Reason))
 		case "2":
 			path := askPath("Fichier a deverrouiller", cfg.DefaultFile)
 			lockPath := lockPathForFile(path, cfg.OutDir)
@@ -43,34 +55,41 @@ func runSecureOps(cfg Config) {
 				fmt.Println("Aucun lock trouve.")
 				break
 			}
+			info, err := readLockInfo(lockPath)
+			if err != nil {
+				fmt.Printf("Erreur lecture lock: %v\n", err)
+				break
+			}
+			printLockInfo(info)
 			if !confirmAction("Confirmer deverrouillage") {
 				fmt.Println("Annule.")
 				break
 			}
-			if err := os.Remove(lockPath); err != nil {
-				fmt.Printf("Erreur suppression lock: %v\n", err)
-				writeAuditLog(cfg.OutDir, fmt.Sprintf("UNLOCK FAIL file=%s err=%v", path, err))
+			if err := releaseLock(lockPath, info); err != nil {
+				fmt.Printf("Erreur deverrouillage: %v\n", err)
+				writeAuditLog(cfg.OutDir, fmt.Sprintf("UNLOCK FAIL file=%s %s err=%v", path, info.auditFields(), err))
 				break
 			}
 			fmt.Printf("Lock supprime: %s\n", lockPath)
-			writeAuditLog(cfg.OutDir, fmt.Sprintf("UNLOCK OK file=%s lock=%s", path, lockPath))
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("UNLOCK OK file=%s lock=%s %s", path, lockPath, info.auditFields()))
 		case "3":
 			path := askPath("Fichier a rendre read-only", cfg.DefaultFile)
 			if !fileExists(path) {
 				fmt.Println("Fichier introuvable ou non valide.")
 				break
 			}
+			warnFileUsers(path)
 			if !confirmAction("Confirmer read-only") {
 				fmt.Println("Annule.")
 				break
 			}
-			if err := setReadOnly(path); err != nil {
-				fmt.Printf("Erreur read-only: %v\n", err)
-				writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY FAIL file=%s err=%v", path, err))
-				break
-			}
-			fmt.Println("Read-only OK.")
-			writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY OK file=%s", path))
+			makeReadOnly(cfg, path)
+		case "4":
+			runLockStatus(cfg)
+		case "5":
+			runListLocks(cfg)
+		case "6":
+			runRestorePermissions(cfg)
 		case "0":
 			return
 		default:
@@ -80,28 +99,114 @@ func runSecureOps(cfg Config) {
 	}
 }
 
-func createLock(filePath, outDir string) (string, error) {
+func createLock(filePath, outDir, reason string, ttlMin int) (string, LockInfo, error) {
 	if err := ensureDir(outDir); err != nil {
-		return "", err
+		return "", LockInfo{}, err
 	}
 	lockPath := lockPathForFile(filePath, outDir)
 	if fileExists(lockPath) {
-		return "", fmt.Errorf("deja verrouille (%s)", lockPath)
+		return "", LockInfo{}, fmt.Errorf("deja verrouille (%s)", lockPath)
 	}
-	if err := os.WriteFile(lockPath, []byte("locked\n"), 0o644); err != nil {
-		return "", err
+	holder, err := startLockDaemon(filePath, ttlMin)
+	if err != nil {
+		return "", LockInfo{}, err
 	}
-	return lockPath, nil
+	host, _ := os.Hostname()
+	info := LockInfo{
+		File: canonicalPath(filePath), User: currentUserName(), Host: host, PID: holder,
+		Locked: time.Now(), Reason: reason, TTLMin: ttlMin,
+	}
+	if err := writeLockInfo(lockPath, info); err != nil {
+		stopLockDaemon(holder, info.File)
+		return "", LockInfo{}, err
+	}
+	if err := indexLock(lockPath, filePath); err != nil {
+		stopLockDaemon(holder, info.File)
+		os.Remove(lockPath)
+		return "", LockInfo{}, err
+	}
+	return lockPath, info, nil
+}
+
+// true si la voie est libre (verrou perime casse), false si le verrou tient
+func breakStaleLock(cfg Config, lockPath string) bool {
+	info, err := readLockInfo(lockPath)
+	if err != nil {
+		fmt.Printf("Erreur lecture lock: %v\n", err)
+		return false
+	}
+	printLockInfo(info)
+	stale := lockStaleReason(info, time.Now())
+	if stale == "" {
+		fmt.Println("Deja verrouille.")
+		return false
+	}
+	fmt.Printf("Verrou perime (%s).\n", stale)
+	if !confirmAction("Casser le verrou perime") {
+		fmt.Println("Annule.")
+		return false
+	}
+	if err := releaseLock(lockPath, info); err != nil {
+		fmt.Printf("Erreur: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("LOCK BREAK FAIL lock=%s file=%s err=%v", lockPath, info.File, err))
+		return false
+	}
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("LOCK BREAK OK lock=%s file=%s %s stale=%q", lockPath, info.File, info.auditFields(), stale))
+	return true
 }
 
 func lockPathForFile(filePath, outDir string) string {
-	base := filepath.Base(filePath)
-	name := strings.TrimSuffix(base, filepath.Ext(base))
-	if name == "" {
-		name = "file"
+	return filepath.Join(outDir, lockNameForFile(filePath))
+}
+
+// l'etat d'origine est journalise avant le changement pour pouvoir le restaurer
+func makeReadOnly(cfg Config, path string) {
+	file := canonicalPath(path)
+	oldMode, oldAttrs, err := readPermissions(file)
+	if err != nil {
+		fmt.Printf("Erreur lecture permissions: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY FAIL file=%s err=%v", path, err))
+		return
+	}
+	entries, err := loadPermissionJournal(cfg.OutDir)
+	if err != nil {
+		// sans journal, le changement ne pourrait pas etre annule
+		fmt.Printf("Erreur lecture journal: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY FAIL file=%s err=journal illisible: %v", path, err))
+		return
+	}
+
+	// l'etat d'origine est journalise avant le chmod: un arret de l'outil
+	// entre les deux laisse toujours de quoi restaurer
+	pending := pendingPermissionChange(entries, file)
+	if pending < 0 {
+		updated := append(entries, PermissionChange{
+			File: file, OldMode: oldMode, NewMode: oldMode &^ 0o222, OldAttrs: oldAttrs, Changed: time.Now(),
+		})
+		if err := savePermissionJournal(cfg.OutDir, updated); err != nil {
+			fmt.Printf("Erreur ecriture journal: %v\n", err)
+			writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY FAIL file=%s err=journal non ecrit: %v", path, err))
+			return
+		}
+	}
+	if err := setReadOnly(file); err != nil {
+		fmt.Printf("Erreur read-only: %v\n", err)
+		writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY FAIL file=%s err=%v", path, err))
+		if pending < 0 {
+			if err := savePermissionJournal(cfg.OutDir, entries); err != nil {
+				fmt.Printf("Erreur ecriture journal: %v\n", err)
+				writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY FAIL file=%s err=journal non restaure: %v", path, err))
+			}
+		}
+		return
+	}
+	newMode, _, _ := readPermissions(file)
+	fmt.Println("Read-only OK.")
+	writeAuditLog(cfg.OutDir, fmt.Sprintf("READONLY OK file=%s old=%s new=%s", path,
+		formatPermissions(oldMode, oldAttrs), formatPermissions(newMode, "")))
+	if pending >= 0 {
+		fmt.Printf("Etat d'origine deja journalise (%s).\n", formatPermissions(entries[pending].OldMode, entries[pending].OldAttrs))
 	}
-	name = sanitizeFileName(name)
-	return filepath.Join(outDir, name+".lock")
 }
 
 func setReadOnly(path string) error {